@@ -7,255 +7,735 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/fatih/color"
 	uuid "github.com/satori/go.uuid"
-	"github.com/tcnksm/go-input"
 	"github.com/tidwall/pretty"
+	"market-sync/chain"
 	"market-sync/client"
-	"regexp"
+	"market-sync/notify"
+	"market-sync/prompt"
+	"market-sync/provenance"
+	"market-sync/syncer"
+	"os"
 	"strconv"
 	"strings"
+	"text/tabwriter"
 )
 
 type Application struct {
 	config    *Config
-	chainlink *client.Chainlink
+	chainlink syncer.ChainlinkAPI
 	market    *client.Market
+	syncer    *syncer.Syncer
+	namer     *JobNamer
+	node      *client.MarketNode
+	// specNodes are the Market nodes each job spec is listed under, by spec ID
+	specNodes  map[string]*client.MarketNode
+	summary    *SyncSummary
+	checkpoint *Checkpoint
+	// failedCreates are the IDs of the specs whose Market job failed to be created,
+	// which may have been created anyway if only the response was lost
+	failedCreates map[string]bool
+	// unfulfillable are the oracles that failed on chain verification, and why
+	unfulfillable map[common.Address]error
+	backend       chain.Backend
 }
 
 type Config struct {
-	UI                      *input.UI
-	ChainlinkEmail          string
-	ChainlinkPassword       string
-	ChainlinkURL            string
-	ChainlinkOracleAddress	common.Address
+	Prompter               prompt.Prompter
+	ChainlinkEmail         string
+	ChainlinkPassword      string
+	ChainlinkURL           string
+	ChainlinkOracleAddress common.Address
 
-	MarketAccessKey         string
-	MarketSecretKey         string
+	MarketAccessKey string
+	MarketSecretKey string
+
+	// ETHRPCURL is the Ethereum RPC the oracle contracts are verified through, if set
+	ETHRPCURL string
+	// MinETHBalance is the fulfillment account's ETH balance below which publishing
+	// is warned about, or refused if RefuseLowBalance is set. Balances aren't checked if nil.
+	MinETHBalance    *client.Eth
+	RefuseLowBalance bool
+	// Signer signs each job spec as it's published, with specs published unsigned if nil
+	Signer *provenance.Signer
+
+	// PageSize is how many items are read per request when listing job specs and jobs
+	PageSize int
+	// Networks add to or override the default chain ID to Market network mappings
+	Networks []*syncer.Network
+
+	Pricer       Pricer
+	NameTemplate string
+	TUI          bool
+	// ContinueOnError carries on to the next job spec when one fails, rather than
+	// stopping the sync or prompting to retry
+	ContinueOnError bool
+	// CheckpointPath is where the sync's progress is saved, if set
+	CheckpointPath string
+	// Resume continues the sync saved at the checkpoint path
+	Resume   bool
+	Metrics  *Metrics
+	Notifier notify.Notifier
+	// Queue is set when job specs are proposed to the queue rather than published
+	Queue Queue
+	// TrustedProposers are the addresses of the operators whose signed publish requests can be approved
+	TrustedProposers []common.Address
 }
 
+// NewApplication connects to the Market, and to the Chainlink node if its URL is
+// set, as commands such as approve only use the Market. Without the Chainlink node,
+// the syncer can only publish job specs that are already prepared.
 func NewApplication(config *Config) (*Application, error) {
-	c, err := client.NewChainlink(&client.ChainlinkClientConfig{
-		Email:    config.ChainlinkEmail,
-		Password: config.ChainlinkPassword,
-		URL:      config.ChainlinkURL,
-	})
+	m, err := client.NewMarket(config.MarketAccessKey, config.MarketSecretKey)
 	if err != nil {
 		return nil, err
 	}
 
-	m, err := client.NewMarket(config.MarketAccessKey, config.MarketSecretKey)
+	namer, err := NewJobNamer(config.NameTemplate)
 	if err != nil {
 		return nil, err
 	}
 
-	return &Application{
-		config:    config,
-		chainlink: c,
-		market:    m,
-	}, nil
+	a := &Application{
+		config:        config,
+		market:        m,
+		namer:         namer,
+		failedCreates: map[string]bool{},
+	}
+	if len(config.ChainlinkURL) > 0 {
+		c, err := client.NewChainlink(&client.ChainlinkClientConfig{
+			Email:    config.ChainlinkEmail,
+			Password: config.ChainlinkPassword,
+			URL:      config.ChainlinkURL,
+		})
+		if err != nil {
+			return nil, err
+		}
+		a.chainlink = c
+	}
+	a.syncer = syncer.NewSyncer(a.chainlink, m, config.ChainlinkOracleAddress)
+	a.syncer.PageSize = config.PageSize
+	a.syncer.Networks = syncer.NewNetworkRegistry(config.Networks...)
+	a.syncer.StatsSampleSize = runStatsSampleSize
+	a.syncer.Signer = config.Signer
+	a.syncer.Warn = func(err error) {
+		color.Red("Warning: unable to read the job's runs, the listing won't include run stats")
+		displayError(err)
+	}
+	return a, nil
 }
 
+// MarketNode returns the Market node of the default oracle, or nil if the default oracle
+// is unknown or unlisted, as job specs are listed under the node of the oracle they fulfil
 func (a *Application) MarketNode() (*client.MarketNode, error) {
+	node, err := a.syncer.Node()
+	if err == syncer.ErrNodeNotFound || err == syncer.ErrNilOracle {
+		color.Yellow("No Market node for the default oracle, only job specs of oracles listed on the Market will be synced")
+		return nil, nil
+	}
+	return node, err
+}
+
+// printNodeDetails prints the oracle address and the details the node reports in its config
+func (a *Application) printNodeDetails() {
 	yellow := color.New(color.FgYellow).SprintFunc()
+	if oracle, err := a.syncer.Oracle(); err == nil {
+		fmt.Printf("%s %s\n", yellow("Oracle Address:"), oracle.String())
+	}
+	cfg, err := a.syncer.NodeConfig()
+	if err != nil {
+		return
+	}
+	attrs := cfg.Data.Attributes
+	if len(attrs.Version) > 0 {
+		fmt.Printf("%s %s\n", yellow("Node Version:"), attrs.Version)
+	}
+	fmt.Printf("%s %d\n", yellow("Chain ID:"), attrs.ETHChainID)
+	if network, err := a.syncer.Network(); err == nil {
+		fmt.Printf("%s %d (%s)\n", yellow("Market Network:"), network.MarketID, network.Name)
+	}
+	if len(attrs.LinkContractAddress) > 0 {
+		fmt.Printf("%s %s\n", yellow("LINK Contract:"), attrs.LinkContractAddress)
+	}
+	if attrs.MinimumContractPayment != nil {
+		fmt.Printf("%s %s\n", yellow("Minimum Payment:"), attrs.MinimumContractPayment.Display())
+	}
+}
 
-	oracleNilError := errors.New("Chainlink oracle address is nil, please ensure chainlink-oracle-address is being passed in as a flag.")
-	cfg, err := a.chainlink.Config()
+func (a *Application) SyncJobSpecs(node *client.MarketNode) error {
+	plan, err := a.plan()
 	if err != nil {
+		return err
+	}
+	if err := a.verifyOracles(plan); err != nil {
+		return err
+	}
+	specs := plan.Specs()
+	a.summary = NewSyncSummary(specs)
+	if err := a.checkBalances(len(specs)); err != nil {
+		return err
+	}
+	if specs, err = a.startCheckpoint(node, specs); err != nil {
+		return err
+	}
+	if len(specs) == 0 {
+		return a.finishSync()
+	}
+	a.notifyUnsynced(specs)
+	a.node = node
+	for _, n := range plan.Nodes() {
+		if err := a.reserveMarketJobNames(n.ID); err != nil {
+			color.Red("Warning: unable to load existing job names from the Market, names won't be checked for collisions")
+			displayError(err)
+		}
+	}
+	if a.config.Pricer != nil {
+		if err := a.promptPricing(specs); err != nil {
+			return err
+		}
+	}
+	for _, spec := range specs {
+		if a.checkpoint.Restore(spec) {
+			color.Yellow("Restored the changes made to job spec %s before the sync stopped", spec.ID)
+		}
+	}
+	if a.config.TUI {
+		for _, spec := range specs {
+			a.checkSecrets(spec)
+			a.checkOracle(spec)
+		}
+		if err := a.reviewJobSpecs(specs); err != nil {
+			return err
+		}
+		return a.finishSync()
+	}
+	for i, spec := range specs {
+		color.Green("Job Spec %d/%d", i+1, len(specs))
+		if err := a.promptJobSpec(spec); err != nil {
+			a.recordOutcome(spec, OutcomeFailed, err)
+			if !a.config.ContinueOnError {
+				return err
+			}
+			displayError(err)
+		}
+	}
+	return a.finishSync()
+}
+
+// startCheckpoint starts saving the sync's progress, returning the specs left to
+// sync if resuming from an earlier sync's checkpoint
+func (a *Application) startCheckpoint(node *client.MarketNode, specs []*client.ChainlinkJobSpec) ([]*client.ChainlinkJobSpec, error) {
+	path := a.config.CheckpointPath
+	if len(path) == 0 {
+		return specs, nil
+	}
+	var nodeId string
+	if node != nil {
+		nodeId = node.ID.String()
+	}
+	if a.config.Resume {
+		c, err := LoadCheckpoint(path)
+		if err != nil {
+			return nil, err
+		} else if c == nil {
+			color.Yellow("No checkpoint found at %s, starting from the first job spec", path)
+		} else if c.NodeID != nodeId {
+			return nil, fmt.Errorf("checkpoint %s is for Market node %s, not %s", path, c.NodeID, nodeId)
+		} else {
+			a.checkpoint = c
+			var remaining []*client.ChainlinkJobSpec
+			for _, spec := range specs {
+				if outcome, ok := c.Processed[spec.ID]; ok {
+					a.summary.Record(spec, outcome, nil)
+				} else {
+					remaining = append(remaining, spec)
+				}
+			}
+			color.Yellow("Resuming from %s, %d job specs were already processed", path, len(specs)-len(remaining))
+			return remaining, nil
+		}
+	} else if c, err := LoadCheckpoint(path); err != nil {
+		return nil, err
+	} else if c != nil && c.NodeID == nodeId {
+		return nil, fmt.Errorf(
+			"checkpoint %s has the progress of an earlier sync of this node, run with --resume to continue it "+
+				"or --checkpoint-file=\"\" to sync without one, or remove the file to start over",
+			path,
+		)
+	}
+	a.checkpoint = NewCheckpoint(path, nodeId)
+	if err := a.checkpoint.Save(); err != nil {
 		return nil, err
 	}
+	return specs, nil
+}
 
-	oracle := a.config.ChainlinkOracleAddress
-	chainId := cfg.Data.Attributes.ETHChainID
+// finishSync removes the checkpoint if every job spec was processed
+func (a *Application) finishSync() error {
+	if err := a.summary.Err(); err != nil {
+		return err
+	}
+	if err := a.checkpoint.Remove(); err != nil {
+		color.Red("Warning: unable to remove the checkpoint file")
+		displayError(err)
+	}
+	return nil
+}
 
-	fmt.Printf("%s %s\n", yellow("Oracle Address:"), oracle.String())
-	if oracle.String() == common.HexToAddress("0x0").String() {
-		return nil, oracleNilError
+// recordOutcome records the outcome of the spec in the summary and checkpoint
+func (a *Application) recordOutcome(spec *client.ChainlinkJobSpec, outcome SpecOutcome, err error) {
+	a.summary.Record(spec, outcome, err)
+	if err := a.checkpoint.Record(spec, outcome); err != nil {
+		color.Red("Warning: unable to save the checkpoint file")
+		displayError(err)
 	}
+}
 
-	node, err := a.market.NodeByOracleAddress(&oracle, chainId)
+// saveProgress saves the changes made to the spec so far to the checkpoint
+func (a *Application) saveProgress(spec *client.ChainlinkJobSpec) {
+	if err := a.checkpoint.Progress(spec); err != nil {
+		color.Red("Warning: unable to save the checkpoint file")
+		displayError(err)
+	}
+}
+
+// Summary returns the outcome of each job spec in the last sync, which is nil until a sync starts
+func (a *Application) Summary() *SyncSummary {
+	return a.summary
+}
+
+// recordPublish records whether the spec was published, replacing the outcome of any
+// earlier attempt. The name of a spec that failed is released, so a retry can reuse it.
+func (a *Application) recordPublish(spec *client.ChainlinkJobSpec, err error) {
 	if err != nil {
-		return nil, errors.New("Chainlink node not found on the Market, create it before running this tool")
+		a.namer.Unclaim(spec.ID)
+	}
+	if a.summary == nil {
+		return
+	} else if err != nil {
+		a.recordOutcome(spec, OutcomeFailed, err)
+	} else if a.config.Queue != nil {
+		a.recordOutcome(spec, OutcomeProposed, nil)
+	} else {
+		a.recordOutcome(spec, OutcomePublished, nil)
 	}
-	return node, nil
 }
 
-func (a *Application) SyncJobSpecs(nodeId uuid.UUID, networkId int) error {
-	yellow := color.New(color.FgYellow).SprintFunc()
+// reviewJobSpecs shows every spec in the review UI, then publishes the approved specs as a batch
+func (a *Application) reviewJobSpecs(specs []*client.ChainlinkJobSpec) error {
+	t := newReviewTUI(a, specs)
+	if submit, err := t.Run(); err != nil {
+		return err
+	} else if !submit {
+		color.Yellow("Review cancelled, no job specs were published")
+		return nil
+	}
 
-	specs, err := a.chainlink.GetSpecs(1, 1)
+	for _, item := range t.items {
+		if item.status != reviewApproved {
+			a.config.Metrics.SpecSkipped()
+			a.recordOutcome(item.spec, OutcomeSkipped, nil)
+		}
+	}
+	approved := t.Approved()
+	color.Green("Publishing %d approved job specs", len(approved))
+	if a.config.Queue != nil {
+		for _, item := range approved {
+			fmt.Printf("%s (%s)\n", item.spec.Name, item.spec.ID)
+			a.syncer.AttachStats(item.spec)
+			a.setReviewResult(item, a.proposeMarketJob(item.spec))
+		}
+		return nil
+	}
+
+	plan := &syncer.Plan{Node: a.node}
+	for _, item := range approved {
+		plan.Actions = append(plan.Actions, &syncer.Action{
+			Type: syncer.ActionCreate,
+			Spec: item.spec,
+			Node: a.nodeFor(item.spec),
+		})
+	}
+	results, err := a.syncer.Apply(plan)
 	if err != nil {
 		return err
 	}
-	specCount := specs.Meta.Count
-	fmt.Printf("%s %d\n\n", yellow("Job Spec Count:"), specCount)
+	for i, r := range results {
+		fmt.Printf("%s (%s)\n", r.Action.Spec.Name, r.Action.Spec.ID)
+		a.setReviewResult(approved[i], a.marketJobCreated(r.Action.Spec, r.MarketJob, r.Err))
+	}
+	return nil
+}
 
-	page := 1
-	loopBatch := 5
-	for i := 0; i < specCount; i = i + loopBatch {
-		specs, err := a.chainlink.GetSpecs(page, loopBatch)
-		if err != nil {
-			return err
+func (a *Application) setReviewResult(item *reviewItem, err error) {
+	a.recordPublish(item.spec, err)
+	if err != nil {
+		item.status, item.err = reviewFailed, err
+		displayError(err)
+	} else {
+		item.status = reviewPublished
+	}
+}
+
+// nodeFor returns the Market node the spec is listed under, defaulting to the node being synced
+func (a *Application) nodeFor(spec *client.ChainlinkJobSpec) *client.MarketNode {
+	if node, ok := a.specNodes[spec.ID]; ok {
+		return node
+	}
+	return a.node
+}
+
+// networkName returns the name of the Market node's network, or its ID if it has no name
+func networkName(node *client.MarketNode) string {
+	if node == nil {
+		return ""
+	} else if len(node.Network.Name) == 0 {
+		return strconv.Itoa(node.Network.ID)
+	}
+	return node.Network.Name
+}
+
+// reserveMarketJobNames stops any generated or given job names colliding with the node's existing Market jobs
+func (a *Application) reserveMarketJobNames(nodeId uuid.UUID) error {
+	it := client.NewJobIterator(a.market.Jobs, nodeId, a.config.PageSize)
+	for it.Next() {
+		a.namer.Reserve(it.Job().Name)
+	}
+	return it.Err()
+}
+
+// plan reconciles the node against the Market, printing the job specs already on the Market
+func (a *Application) plan() (*syncer.Plan, error) {
+	yellow := color.New(color.FgYellow).SprintFunc()
+
+	plan, err := a.syncer.Plan()
+	if err != nil {
+		return nil, err
+	}
+	fmt.Printf("%s %d\n\n", yellow("Job Spec Count:"), plan.SpecCount)
+	a.specNodes = map[string]*client.MarketNode{}
+	for _, action := range plan.Actions {
+		if action.Node != nil {
+			a.specNodes[action.Spec.ID] = action.Node
 		}
-		for j, spec := range specs.Data {
-			color.Green("Job Spec %d", i+j+1)
-			exists, err := a.market.JobExists(spec.ID, networkId)
-			if err != nil {
-				return err
-			} else if exists {
-				fmt.Printf("%s %s\n", yellow("Job ID Exists on Market:"), spec.ID)
-				continue
+	}
+	if nodes := plan.Nodes(); len(nodes) > 1 {
+		for _, n := range nodes {
+			fmt.Printf("%s %s (oracle %s)\n", yellow("Market Node:"), n.ID.String(), n.OracleAddress.String())
+		}
+		fmt.Println()
+	}
+	for _, action := range plan.Skips() {
+		if action.Reason == syncer.ReasonExists {
+			fmt.Printf("%s %s\n", yellow("Job ID Exists on Market:"), action.Spec.ID)
+		} else {
+			color.Red("Skipping job spec %s, %s", action.Spec.ID, action.Reason)
+		}
+	}
+	for _, action := range plan.Duplicates() {
+		fmt.Printf("%s %s is the same job as %s\n", yellow("Duplicate Job Spec:"), action.Spec.ID, action.DuplicateOf)
+	}
+	for _, action := range plan.Changed() {
+		color.Red("Job spec %s has changed since it was published to the Market", action.Spec.ID)
+	}
+	for _, actions := range plan.Shared() {
+		var listings []string
+		for _, action := range actions {
+			listings = append(listings, fmt.Sprintf("%s (node %s)", action.Spec.ID, action.Node.ID.String()))
+		}
+		fmt.Printf("%s %s\n", yellow("Same Job Under the Node's Market Nodes:"), strings.Join(listings, ", "))
+	}
+	unsynced := len(plan.Creates())
+	fmt.Printf("\n%s %d\n\n", yellow("Job Specs to Sync:"), unsynced)
+	a.config.Metrics.SpecsSeen(plan.SpecCount, unsynced)
+	a.config.Metrics.Reconciled()
+	return plan, nil
+}
+
+// promptPricing previews the cost the pricer gives each spec, and sets them as
+// the spec's cost if accepted. Specs that couldn't be priced are prompted for as normal.
+func (a *Application) promptPricing(specs []*client.ChainlinkJobSpec) error {
+	min, err := a.minimumContractPayment()
+	if err != nil {
+		return err
+	}
+
+	prices := make([]*client.Link, len(specs))
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintf(w, "JOB ID\tINITIATORS\tTASKS\tCOST (LINK)\tCOST (JUELS)\t\n")
+	for i, spec := range specs {
+		cost, juels := "error", "-"
+		if price, err := a.config.Pricer.Price(spec, min); err != nil {
+			cost += ": " + err.Error()
+		} else {
+			prices[i] = price
+			cost, juels = price.LinkString(), price.String()
+			if min != nil && price.Cmp(min) < 0 {
+				cost += " (below minimum)"
 			}
-			spec.NodeID = &nodeId
-			a.promptJobSpec(spec)
 		}
-		page++
+		_, _ = fmt.Fprintf(
+			w,
+			"%s\t%s\t%s\t%s\t%s\t\n",
+			spec.ID,
+			strings.Join(initiatorTypes(spec), ","),
+			strings.Join(taskTypes(spec), ","),
+			cost,
+			juels,
+		)
+	}
+	_ = w.Flush()
+	fmt.Println()
+
+	if ok, err := a.config.Prompter.Confirm(
+		fmt.Sprintf("Use these %s pricing costs?", a.config.Pricer.Strategy()),
+		false,
+	); err != nil {
+		return err
+	} else if !ok {
+		return nil
+	}
+	for i, spec := range specs {
+		if prices[i] != nil {
+			spec.MinPayment = prices[i].String()
+		}
 	}
 	return nil
 }
 
-func (a *Application) promptJobSpec(spec *client.ChainlinkJobSpec) {
+func (a *Application) promptJobSpec(spec *client.ChainlinkJobSpec) error {
 	a.outputJSON(spec)
-	if answer, err := a.config.UI.Ask("Sync this job spec to the Market? [y/n]", &input.Options{
-		Default:      "n",
-		Loop:         true,
-		Required:     true,
-		ValidateFunc: booleanInputValidation,
-	}); err != nil {
-		exit(err)
-	} else if answer == "y" {
-		a.syncJob(spec)
+	a.checkSecrets(spec)
+	a.checkOracle(spec)
+	if ok, err := a.config.Prompter.Confirm("Sync this job spec to the Market?", false); err != nil {
+		return err
+	} else if ok {
+		return a.syncJob(spec)
 	}
+	a.config.Metrics.SpecSkipped()
+	a.recordOutcome(spec, OutcomeSkipped, nil)
+	return nil
 }
 
-func (a *Application) promptJobName() string {
-	if answer, err := a.config.UI.Ask("Job name", &input.Options{
-		Loop:     true,
+func (a *Application) promptJobName(spec *client.ChainlinkJobSpec) (string, error) {
+	// the spec's own name isn't taken by itself, so it's released while renaming,
+	// and claimed again if prompting fails
+	owned := a.namer.Unclaim(spec.ID)
+	// a name restored from a checkpoint is kept, unless it's since been taken
+	name := spec.Name
+	if len(name) > 0 && a.namer.Taken(name) {
+		name = ""
+	}
+	if len(name) == 0 && a.namer.HasTemplate() {
+		var err error
+		if name, err = a.namer.Name(spec, networkName(a.nodeFor(spec))); err != nil {
+			displayError(err)
+		}
+	}
+	answer, err := a.config.Prompter.Ask("Job name", &prompt.Options{
+		Default:  name,
 		Required: true,
-		ValidateFunc: func(s string) error {
-			if matcher, err := regexp.Compile(`^[a-zA-Z0-9_\-\.\ \+\>\=]{2,30}$`); err != nil {
-				return err
-			} else if !matcher.Match([]byte(s)) {
+		Validate: func(s string) error {
+			if !jobNameMatcher.MatchString(s) {
 				return errors.New("invalid job name, must be: (2-30 length, a-z, A-Z, 0-9, ), -, ., , +, >, =)")
+			} else if a.namer.Taken(s) {
+				return fmt.Errorf("job name %s is already used on the Market", s)
 			}
 			return nil
 		},
-	}); err != nil {
-		exit(err)
-	} else {
-		return answer
+	})
+	if err != nil {
+		if len(owned) > 0 {
+			a.namer.Claim(spec.ID, owned)
+		}
+		return "", err
 	}
-	return ""
+	a.namer.Claim(spec.ID, answer)
+	return answer, nil
 }
 
-func (a *Application) promptJobCost() string {
-	if answer, err := a.config.UI.Ask("Job cost", &input.Options{
-		Default:  "100000000000000000",
-		Loop:     true,
+func (a *Application) promptJobCost() (string, error) {
+	answer, err := a.config.Prompter.Ask("Job cost (LINK or juels)", &prompt.Options{
+		Default:  "0.1 LINK",
 		Required: true,
-		ValidateFunc: func(s string) error {
-			if _, err := strconv.ParseInt(s, 10, 64); err != nil {
-				return errors.New("answer must be an int64")
+		Validate: func(s string) error {
+			if _, err := client.ParseLink(s); err != nil {
+				return err
 			}
 			return nil
 		},
-	}); err != nil {
-		exit(err)
-	} else {
-		return answer
+	})
+	if err != nil {
+		return "", err
 	}
-	return ""
+	cost, _ := client.ParseLink(answer)
+	a.displayJobCost(cost)
+	return cost.String(), nil
 }
 
-func (a *Application) promptRetry(spec *client.ChainlinkJobSpec) {
-	if answer, err := a.config.UI.Ask("Retry adding this job? [y/n]", &input.Options{
-		Default:      "n",
-		Loop:         true,
-		Required:     true,
-		ValidateFunc: booleanInputValidation,
-	}); err != nil {
-		return
-	} else if answer == "y" {
-		a.syncJob(spec)
+func (a *Application) displayJobCost(cost *client.Link) {
+	yellow := color.New(color.FgYellow).SprintFunc()
+	fmt.Printf("%s %s\n", yellow("Job Cost:"), cost.Display())
+
+	min, err := a.minimumContractPayment()
+	if err != nil {
+		displayError(err)
+	} else if min != nil && cost.Cmp(min) < 0 {
+		color.Red(
+			"Warning: job cost is below the node's MINIMUM_CONTRACT_PAYMENT of %s, requests at this cost will be rejected by the node",
+			min.Display(),
+		)
+	}
+}
+
+func (a *Application) minimumContractPayment() (*client.Link, error) {
+	cfg, err := a.syncer.NodeConfig()
+	if err != nil {
+		return nil, err
+	}
+	return cfg.Data.Attributes.MinimumContractPayment, nil
+}
+
+func (a *Application) promptRetry(spec *client.ChainlinkJobSpec) error {
+	if ok, err := a.config.Prompter.Confirm("Retry adding this job?", false); err != nil {
+		return err
+	} else if ok {
+		return a.syncJob(spec)
 	}
+	return nil
 }
 
 func (a *Application) promptEdit(spec *client.ChainlinkJobSpec) error {
-	if answer, err := a.config.UI.Ask("Edit job spec parameters? [y/n]", &input.Options{
-		Default:      "n",
-		Loop:         true,
-		Required:     true,
-		ValidateFunc: booleanInputValidation,
-	}); err != nil {
+	if ok, err := a.config.Prompter.Confirm("Edit job spec parameters?", false); err != nil {
 		return err
-	} else if answer == "n" {
+	} else if !ok {
 		return nil
 	}
 
-	var types []string
-	taskMap := map[string]*client.ChainlinkTaskSpec{}
-	for _, t := range spec.Attributes.Tasks {
-		types = append(types, t.Type)
-		taskMap[t.Type] = t
-	}
-	if len(types) == 0 {
-		return errors.New("no job spec tasks")
-	}
-	answer, err := a.config.UI.Select("Select task type", types, &input.Options{})
-	if err != nil {
+	e := newSpecEditor(a.config.Prompter, spec)
+	e.onChange = a.saveProgress
+	return e.Run()
+}
+
+// syncJob prompts for the job's details and publishes it, only returning an error
+// if prompting fails, as publishing errors are recorded and can be retried
+func (a *Application) syncJob(spec *client.ChainlinkJobSpec) error {
+	var err error
+	if spec.Name, err = a.promptJobName(spec); err != nil {
 		return err
 	}
-	t := taskMap[answer]
-	var params []string
-	for k := range t.Params {
-		params = append(params, k)
-	}
-	if len(params) == 0 {
-		return errors.New("no parameters for this task type")
-	}
-	if key, err := a.config.UI.Select("Select parameter", params, &input.Options{}); err != nil {
-		return err
-	} else if value, err := a.config.UI.Ask("Enter new value", &input.Options{
-		Default: fmt.Sprint(t.Params[key]),
-	}); err != nil {
-		return err
+	a.saveProgress(spec)
+	if len(spec.MinPayment) == 0 {
+		if spec.MinPayment, err = a.promptJobCost(); err != nil {
+			return err
+		}
+	} else if cost, err := client.ParseLink(spec.MinPayment); err != nil {
+		displayError(err)
+		if spec.MinPayment, err = a.promptJobCost(); err != nil {
+			return err
+		}
 	} else {
-		t.Params[key] = value
+		spec.MinPayment = cost.String()
+		a.displayJobCost(cost)
 	}
-	return a.promptEdit(spec)
+	a.saveProgress(spec)
+	if err := a.promptEdit(spec); err != nil {
+		return a.syncFailed(spec, err)
+	} else if err := a.publish(spec); err != nil {
+		return a.syncFailed(spec, err)
+	}
+	a.recordPublish(spec, nil)
+	return nil
 }
 
-func (a *Application) syncJob(spec *client.ChainlinkJobSpec) {
-	spec.Name = a.promptJobName()
-	if len(spec.MinPayment) == 0 {
-		spec.MinPayment = a.promptJobCost()
+// syncFailed records the spec as failed, prompting to retry unless continuing on errors
+func (a *Application) syncFailed(spec *client.ChainlinkJobSpec, err error) error {
+	a.recordPublish(spec, err)
+	displayError(err)
+	if a.config.ContinueOnError {
+		return nil
 	}
-	if err := a.promptEdit(spec); err != nil {
-		displayError(err)
-		a.promptRetry(spec)
-	} else if err := a.createMarketJob(spec); err != nil {
-		displayError(err)
-		a.promptRetry(spec)
+	return a.promptRetry(spec)
+}
+
+// publish creates the job on the Market, or proposes it to the queue if one is set
+func (a *Application) publish(spec *client.ChainlinkJobSpec) error {
+	a.syncer.AttachStats(spec)
+	if a.config.Queue != nil {
+		return a.proposeMarketJob(spec)
 	}
+	return a.createMarketJob(spec)
 }
 
+// createMarketJob publishes the spec, checking the Market for the job first if an
+// earlier attempt failed so a retry doesn't list it twice
 func (a *Application) createMarketJob(spec *client.ChainlinkJobSpec) error {
-	id, err := a.market.CreateJob(spec)
+	if node := a.nodeFor(spec); a.failedCreates[spec.ID] && node != nil {
+		if exists, err := a.market.JobExists(spec.ID, node.Network.ID); err != nil {
+			return err
+		} else if exists {
+			delete(a.failedCreates, spec.ID)
+			color.Yellow("Job was created on the Market by an earlier attempt")
+			return nil
+		}
+	}
+	id, err := a.syncer.Publish(spec)
 	if err != nil {
+		a.failedCreates[spec.ID] = true
+	} else {
+		delete(a.failedCreates, spec.ID)
+	}
+	return a.marketJobCreated(spec, id, err)
+}
+
+// marketJobCreated records the outcome of creating the spec's Market job
+func (a *Application) marketJobCreated(spec *client.ChainlinkJobSpec, id *client.MarketCreated, err error) error {
+	if err != nil {
+		a.config.Metrics.SpecFailed()
+		e := notify.NewEvent(notify.EventJobFailed, spec.ID)
+		e.JobName, e.Error = spec.Name, err.Error()
+		a.notify(e)
 		return err
 	}
+	a.config.Metrics.SpecSynced()
+	e := notify.NewEvent(notify.EventJobCreated, spec.ID)
+	e.JobName, e.MarketJobID = spec.Name, id.ID.String()
+	a.notify(e)
 	green := color.New(color.FgGreen).SprintFunc()
 	fmt.Printf("%s %s\n", green("Job created:"), id.ID.String())
 	return nil
 }
 
-func (a *Application) outputJSON(obj interface{}) {
-	b, _ := json.Marshal(obj)
-	fmt.Println(string(pretty.Color(pretty.Pretty(b), nil)))
+// checkSecrets warns about any parameters in the spec that look like secrets
+func (a *Application) checkSecrets(spec *client.ChainlinkJobSpec) {
+	found := detectSecrets(spec)
+	if len(found) == 0 {
+		return
+	}
+	color.Red("Warning: job spec %s may contain secrets, edit them out before syncing:", spec.ID)
+	for _, f := range found {
+		color.Red("  - %s", f)
+	}
+	e := notify.NewEvent(notify.EventSecretDetected, spec.ID)
+	e.Details = found
+	a.notify(e)
 }
 
-func booleanInputValidation(s string) error {
-	i := strings.ToLower(s)
-	if i != "y" && i != "n" {
-		return errors.New("Answer needs to be y/n")
+func (a *Application) notifyUnsynced(specs []*client.ChainlinkJobSpec) {
+	var ids []string
+	for _, spec := range specs {
+		ids = append(ids, spec.ID)
 	}
-	return nil
+	a.notify(notify.NewEvent(notify.EventUnsyncedJobs, ids...))
+}
+
+func (a *Application) notify(e *notify.Event) {
+	if err := a.config.Notifier.Notify(e); err != nil {
+		color.Red("Warning: unable to send %s notification", e.Type)
+		displayError(err)
+	}
+}
+
+func (a *Application) outputJSON(obj interface{}) {
+	b, _ := json.Marshal(obj)
+	fmt.Println(string(pretty.Color(pretty.Pretty(b), nil)))
 }
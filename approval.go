@@ -0,0 +1,238 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/fatih/color"
+	uuid "github.com/satori/go.uuid"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"market-sync/client"
+	"net/http"
+	"os"
+	"time"
+)
+
+const (
+	QueueFileFlag = "queue-file"
+	QueueURLFlag  = "queue-url"
+	QueueAddrFlag = "queue-addr"
+	// TrustedProposersFlag are the addresses of the operators trusted to propose job specs
+	TrustedProposersFlag = "trusted-proposers"
+
+	approveActionApprove = "Approve and publish to the Market"
+	approveActionReject  = "Reject and remove from the queue"
+	approveActionSkip    = "Skip"
+)
+
+func generateProposeCmd() *cobra.Command {
+	newcmd := &cobra.Command{
+		Use:   "propose",
+		Short: "Sync job specs as publish requests to the queue, to be approved by a different operator",
+		Args:  cobra.MaximumNArgs(0),
+		Run:   runPropose,
+	}
+	addSyncFlags(newcmd)
+	addQueueFlags(newcmd)
+	return newcmd
+}
+
+func generateApproveCmd() *cobra.Command {
+	newcmd := &cobra.Command{
+		Use:   "approve",
+		Short: "Review publish requests in the queue, publishing the approved requests to the Market",
+		Args:  cobra.MaximumNArgs(0),
+		Run:   runApprove,
+	}
+	addQueueFlags(newcmd)
+	newcmd.Flags().StringSlice(TrustedProposersFlag, nil, "addresses of the operators whose signed publish requests can be approved")
+	return newcmd
+}
+
+func generateQueueCmd() *cobra.Command {
+	newcmd := &cobra.Command{
+		Use:   "queue",
+		Short: "Manage the publish request queue",
+	}
+	serve := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve the queue file over HTTP, for use with --queue-url",
+		Args:  cobra.MaximumNArgs(0),
+		Run:   runQueueServe,
+	}
+	serve.Flags().String(QueueFileFlag, "market-sync-queue.json", "file publish requests are stored in")
+	serve.Flags().String(QueueAddrFlag, "127.0.0.1:8090", "address to serve the queue on, which is unauthenticated so must stay on localhost")
+	newcmd.AddCommand(serve)
+	return newcmd
+}
+
+func addQueueFlags(cmd *cobra.Command) {
+	cmd.Flags().String(QueueFileFlag, "market-sync-queue.json", "file publish requests are stored in")
+	cmd.Flags().String(QueueURLFlag, "", "url of a queue served by `market-sync queue serve`, used instead of the queue file")
+}
+
+func queueFromFlags() Queue {
+	if url := viper.GetString(QueueURLFlag); len(url) > 0 {
+		return NewHTTPQueue(url)
+	}
+	return NewFileQueue(viper.GetString(QueueFileFlag))
+}
+
+func runPropose(_ *cobra.Command, _ []string) {
+	yellow := color.New(color.FgYellow).SprintFunc()
+	color.Blue("Starting the Market Sync CLI, proposing job specs for approval")
+	requireFlags(append(nodeFlags, marketFlags...)...)
+	a, err := NewApplication(newSyncConfig(&Config{Queue: queueFromFlags()}))
+	if err != nil {
+		exit(err)
+	}
+	if a.config.Signer == nil {
+		exit(fmt.Errorf("publish requests are signed by their proposer, set --%s to the proposer's key", SigningKeystoreFlag))
+	}
+	node, err := a.MarketNode()
+	if err != nil {
+		exit(err)
+	}
+	if node != nil {
+		fmt.Printf("%s %s\n", yellow("Market Node ID:"), node.ID.String())
+	}
+	syncErr := a.SyncJobSpecs(node)
+	if summary := a.Summary(); summary != nil {
+		_ = summary.Write(os.Stdout)
+	}
+	if syncErr != nil {
+		exit(syncErr)
+	}
+	color.Blue("Proposals Complete, run `market-sync approve` as a different operator to publish them")
+	exit(nil)
+}
+
+func runApprove(_ *cobra.Command, _ []string) {
+	color.Blue("Starting the Market Sync CLI, approving proposed job specs")
+	requireFlags(marketFlags...)
+	config := newConfig(&Config{})
+	for _, address := range viper.GetStringSlice(TrustedProposersFlag) {
+		if !common.IsHexAddress(address) {
+			exit(fmt.Errorf("invalid %s address %q", TrustedProposersFlag, address))
+		}
+		config.TrustedProposers = append(config.TrustedProposers, common.HexToAddress(address))
+	}
+	a, err := NewApplication(config)
+	if err != nil {
+		exit(err)
+	}
+	if err := a.ApprovePublishRequests(queueFromFlags()); err != nil {
+		exit(err)
+	}
+	color.Blue("Approvals Complete")
+	exit(nil)
+}
+
+func runQueueServe(_ *cobra.Command, _ []string) {
+	addr := viper.GetString(QueueAddrFlag)
+	color.Blue("Serving the publish request queue on %s", addr)
+	if err := http.ListenAndServe(addr, QueueHandler(NewFileQueue(viper.GetString(QueueFileFlag)))); err != nil {
+		exit(err)
+	}
+}
+
+// proposeMarketJob adds the spec to the queue rather than publishing it
+func (a *Application) proposeMarketJob(spec *client.ChainlinkJobSpec) error {
+	r := &PublishRequest{
+		ID:         uuid.NewV4().String(),
+		Spec:       spec,
+		ProposedBy: a.market.ActiveUser().ID,
+		ProposedAt: time.Now().UTC(),
+	}
+	if node := a.nodeFor(spec); node != nil {
+		r.NetworkID = node.Network.ID
+	}
+	if err := r.Sign(a.config.Signer); err != nil {
+		return err
+	} else if err := a.config.Queue.Add(r); err != nil {
+		return err
+	}
+	green := color.New(color.FgGreen).SprintFunc()
+	fmt.Printf("%s %s\n", green("Publish request proposed:"), r.ID)
+	return nil
+}
+
+// ApprovePublishRequests prompts to approve every request in the queue. Requests can
+// only be approved if they're signed by a trusted proposer, and by a different Market
+// user and key than the ones that proposed them.
+func (a *Application) ApprovePublishRequests(q Queue) error {
+	yellow := color.New(color.FgYellow).SprintFunc()
+	approver := a.market.ActiveUser().ID
+	if uuid.Equal(approver, uuid.Nil) {
+		return errors.New("unable to identify the Market user, so can't ensure they didn't propose the requests")
+	} else if len(a.config.TrustedProposers) == 0 {
+		return errors.New("no trusted proposers, so no publish request can be approved")
+	}
+	trusted := map[common.Address]bool{}
+	for _, address := range a.config.TrustedProposers {
+		trusted[address] = true
+	}
+
+	requests, err := q.List()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s %d\n\n", yellow("Publish Requests:"), len(requests))
+	for i, r := range requests {
+		color.Green("Publish Request %d/%d", i+1, len(requests))
+		a.outputJSON(r.Spec)
+		fmt.Printf("%s %s\n", yellow("Request ID:"), r.ID)
+		fmt.Printf("%s %s\n", yellow("Job Name:"), r.Spec.Name)
+		if cost, err := client.ParseLink(r.Spec.MinPayment); err == nil {
+			fmt.Printf("%s %s\n", yellow("Job Cost:"), cost.Display())
+		}
+		fmt.Printf("%s %s at %s\n", yellow("Proposed By:"), r.ProposedBy, r.ProposedAt.Format(time.RFC3339))
+		fmt.Printf("%s %s\n", yellow("Proposer Key:"), r.Proposer.String())
+		a.checkSecrets(r.Spec)
+
+		if err := r.Validate(); err != nil {
+			color.Red("Skipping, %s\n", err)
+			continue
+		} else if !trusted[r.Proposer] {
+			color.Red("Skipping, %s isn't a trusted proposer\n", r.Proposer.String())
+			continue
+		} else if uuid.Equal(r.ProposedBy, approver) || (a.config.Signer != nil && a.config.Signer.Address() == r.Proposer) {
+			color.Yellow("Skipping, this request was proposed by you and must be approved by a different operator\n")
+			continue
+		}
+		action, err := a.config.Prompter.Select(
+			"Approve this publish request?",
+			[]string{approveActionApprove, approveActionReject, approveActionSkip},
+		)
+		if err != nil {
+			return err
+		}
+		switch action {
+		case approveActionApprove:
+			if err := a.approvePublishRequest(q, r); err != nil {
+				displayError(err)
+			}
+		case approveActionReject:
+			if err := q.Remove(r.ID); err != nil {
+				displayError(err)
+			} else {
+				color.Yellow("Publish request rejected")
+			}
+		}
+	}
+	return nil
+}
+
+func (a *Application) approvePublishRequest(q Queue, r *PublishRequest) error {
+	if exists, err := a.market.JobExists(r.Spec.ID, r.NetworkID); err != nil {
+		return err
+	} else if exists {
+		color.Yellow("Job already exists on the Market, removing the request")
+		return q.Remove(r.ID)
+	}
+	if err := a.createMarketJob(r.Spec); err != nil {
+		return err
+	}
+	return q.Remove(r.ID)
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/fatih/color"
+	"time"
+)
+
+// balanceTimeout is how long reading the node's balances can take
+const balanceTimeout = 30 * time.Second
+
+// checkBalances reads the node's balances into the summary, warning if the
+// fulfillment account is below the minimum ETH balance, or refusing to sync if
+// that's configured, as requests to the new jobs would fail
+func (a *Application) checkBalances(unsynced int) error {
+	if a.config.MinETHBalance == nil || unsynced == 0 {
+		return nil
+	}
+	backend, err := a.ethBackend()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), balanceTimeout)
+	defer cancel()
+	check, err := a.syncer.CheckBalances(ctx, backend, a.config.MinETHBalance)
+	if err != nil {
+		color.Red("Warning: unable to read the node's balances, they won't be checked")
+		displayError(err)
+		return nil
+	}
+	a.summary.Balances = check
+	if err := check.Err(); err == nil {
+		return nil
+	} else if a.config.RefuseLowBalance {
+		return fmt.Errorf("refusing to publish jobs, %w", err)
+	} else {
+		color.Red("Warning: %s, requests to the published jobs may fail", err)
+		return nil
+	}
+}
@@ -0,0 +1,59 @@
+package chain
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"math/big"
+	"strings"
+)
+
+// LinkTokenABI is the part of the LINK token contract's ABI read by the sync
+const LinkTokenABI = `[
+	{"constant":true,"inputs":[{"name":"_owner","type":"address"}],"name":"balanceOf","outputs":[{"name":"balance","type":"uint256"}],"payable":false,"stateMutability":"view","type":"function"}
+]`
+
+// Backend is a contract backend that can also read account balances, which
+// ethclient.Client and the simulated backend both are
+type Backend interface {
+	bind.ContractBackend
+	BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
+}
+
+// Balance is the ETH and LINK balance of an account, in wei and juels
+type Balance struct {
+	Address common.Address
+	ETH     *big.Int
+	// LINK is nil if the LINK contract is unknown
+	LINK *big.Int
+}
+
+// ReadBalances reads the ETH balance of each account, along with its LINK balance
+// if the LINK contract's address isn't empty
+func ReadBalances(ctx context.Context, backend Backend, link common.Address, accounts []common.Address) ([]*Balance, error) {
+	var token *bind.BoundContract
+	if link != (common.Address{}) {
+		parsed, err := abi.JSON(strings.NewReader(LinkTokenABI))
+		if err != nil {
+			return nil, err
+		}
+		token = bind.NewBoundContract(link, parsed, backend, backend, backend)
+	}
+	var balances []*Balance
+	for _, account := range accounts {
+		eth, err := backend.BalanceAt(ctx, account, nil)
+		if err != nil {
+			return nil, err
+		}
+		b := &Balance{Address: account, ETH: eth}
+		if token != nil {
+			b.LINK = new(big.Int)
+			if err := token.Call(&bind.CallOpts{Context: ctx}, &b.LINK, "balanceOf", account); err != nil {
+				return nil, err
+			}
+		}
+		balances = append(balances, b)
+	}
+	return balances, nil
+}
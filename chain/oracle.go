@@ -0,0 +1,120 @@
+// Package chain reads the oracle contracts the node fulfils requests for. Everything
+// takes a bind.ContractBackend, which ethclient.Client and go-ethereum's
+// simulated backend both implement.
+package chain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"strings"
+)
+
+// OracleABI is the part of the Chainlink Oracle contract's ABI read by the sync
+const OracleABI = `[
+	{"constant":true,"inputs":[{"name":"_node","type":"address"}],"name":"getAuthorizationStatus","outputs":[{"name":"","type":"bool"}],"payable":false,"stateMutability":"view","type":"function"},
+	{"constant":true,"inputs":[],"name":"owner","outputs":[{"name":"","type":"address"}],"payable":false,"stateMutability":"view","type":"function"}
+]`
+
+var ErrNoContract = errors.New("no contract deployed")
+
+// Dial connects to an Ethereum RPC URL
+func Dial(url string) (*ethclient.Client, error) {
+	return ethclient.Dial(url)
+}
+
+// Oracle reads a Chainlink Oracle contract
+type Oracle struct {
+	address  common.Address
+	backend  bind.ContractBackend
+	contract *bind.BoundContract
+}
+
+func NewOracle(address common.Address, backend bind.ContractBackend) (*Oracle, error) {
+	parsed, err := abi.JSON(strings.NewReader(OracleABI))
+	if err != nil {
+		return nil, err
+	}
+	return &Oracle{
+		address:  address,
+		backend:  backend,
+		contract: bind.NewBoundContract(address, parsed, backend, backend, backend),
+	}, nil
+}
+
+func (o *Oracle) Address() common.Address {
+	return o.address
+}
+
+// Exists returns whether there's a contract deployed at the oracle address
+func (o *Oracle) Exists(ctx context.Context) (bool, error) {
+	code, err := o.backend.CodeAt(ctx, o.address, nil)
+	if err != nil {
+		return false, err
+	}
+	return len(code) > 0, nil
+}
+
+// IsAuthorized returns whether the address is allowed to fulfil the oracle's requests
+func (o *Oracle) IsAuthorized(ctx context.Context, node common.Address) (bool, error) {
+	var authorized bool
+	err := o.contract.Call(&bind.CallOpts{Context: ctx}, &authorized, "getAuthorizationStatus", node)
+	return authorized, err
+}
+
+// Owner returns the owner of the oracle contract
+func (o *Oracle) Owner(ctx context.Context) (common.Address, error) {
+	var owner common.Address
+	err := o.contract.Call(&bind.CallOpts{Context: ctx}, &owner, "owner")
+	return owner, err
+}
+
+// KeyAuthorization is whether one of the node's keys can fulfil the oracle's requests
+type KeyAuthorization struct {
+	Address    common.Address `json:"address"`
+	Authorized bool           `json:"authorized"`
+}
+
+// Verification is the result of checking the oracle contract against the node's keys
+type Verification struct {
+	Oracle common.Address      `json:"oracle"`
+	Exists bool                `json:"exists"`
+	Keys   []*KeyAuthorization `json:"keys"`
+}
+
+// Err returns why the oracle's requests could never be fulfilled by the node, if they can't
+func (v *Verification) Err() error {
+	if !v.Exists {
+		return fmt.Errorf("oracle %s: %w", v.Oracle.String(), ErrNoContract)
+	}
+	for _, k := range v.Keys {
+		if k.Authorized {
+			return nil
+		}
+	}
+	return fmt.Errorf("oracle %s: none of the node's %d ETH keys are authorized to fulfil its requests", v.Oracle.String(), len(v.Keys))
+}
+
+// Verify checks the oracle contract exists, and which of the keys are authorized to fulfil its requests
+func Verify(ctx context.Context, backend bind.ContractBackend, oracle common.Address, keys []common.Address) (*Verification, error) {
+	o, err := NewOracle(oracle, backend)
+	if err != nil {
+		return nil, err
+	}
+	v := &Verification{Oracle: oracle}
+	if v.Exists, err = o.Exists(ctx); err != nil || !v.Exists {
+		return v, err
+	}
+	for _, key := range keys {
+		authorized, err := o.IsAuthorized(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		v.Keys = append(v.Keys, &KeyAuthorization{Address: key, Authorized: authorized})
+	}
+	return v, nil
+}
@@ -0,0 +1,151 @@
+package chain
+
+import (
+	"context"
+	"errors"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/crypto"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+var (
+	stubOwner       = common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	authorizedKey   = common.HexToAddress("0x00000000000000000000000000000000000000bb")
+	unauthorizedKey = common.HexToAddress("0x00000000000000000000000000000000000000cc")
+)
+
+// stubOracleCode is the creation code of a contract answering owner() with owner, and
+// getAuthorizationStatus(address) with whether the address is authorized
+func stubOracleCode(owner, authorized common.Address) []byte {
+	selector := func(sig string) []byte {
+		return crypto.Keccak256([]byte(sig))[:4]
+	}
+	push20 := func(code []byte, a common.Address) []byte {
+		return append(append(code, 0x73), a.Bytes()...)
+	}
+	ret := []byte{0x60, 0x00, 0x52, 0x60, 0x20, 0x60, 0x00, 0xf3} // MSTORE at 0, RETURN 32 bytes
+
+	// the selector is the first 4 bytes of the calldata, shifted down by dividing by 2^224
+	code := []byte{0x60, 0x00, 0x35, 0x7c, 0x01}
+	code = append(code, make([]byte, 28)...)
+	code = append(code, 0x90, 0x04)
+	// jump to the owner() or getAuthorizationStatus(address) handler, reverting for anything else
+	code = append(append(code, 0x80, 0x63), selector("owner()")...)
+	ownerJump := len(code) + 2
+	code = append(code, 0x14, 0x60, 0x00, 0x57)
+	code = append(append(code, 0x63), selector("getAuthorizationStatus(address)")...)
+	authJump := len(code) + 2
+	code = append(code, 0x14, 0x60, 0x00, 0x57)
+	code = append(code, 0x60, 0x00, 0x80, 0xfd)
+
+	code[ownerJump] = byte(len(code))
+	code = push20(append(code, 0x5b), owner)
+	code = append(code, ret...)
+
+	code[authJump] = byte(len(code))
+	code = push20(append(code, 0x5b, 0x60, 0x04, 0x35), authorized)
+	code = append(append(code, 0x14), ret...)
+
+	// the creation code copies the runtime code after it into memory and returns it
+	create := []byte{0x61, 0x00, byte(len(code)), 0x80, 0x60, 0x0c, 0x60, 0x00, 0x39, 0x60, 0x00, 0xf3}
+	return append(create, code...)
+}
+
+func deployStubOracle(t *testing.T) (*backends.SimulatedBackend, common.Address) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth := bind.NewKeyedTransactor(key)
+	backend := backends.NewSimulatedBackend(core.GenesisAlloc{
+		auth.From: {Balance: new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)},
+	}, 8000000)
+	parsed, err := abi.JSON(strings.NewReader(OracleABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	address, _, _, err := bind.DeployContract(auth, parsed, stubOracleCode(stubOwner, authorizedKey), backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend.Commit()
+	return backend, address
+}
+
+func TestOracle(t *testing.T) {
+	backend, address := deployStubOracle(t)
+	ctx := context.Background()
+	o, err := NewOracle(address, backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if exists, err := o.Exists(ctx); err != nil || !exists {
+		t.Errorf("expected the oracle to exist, got %v, %v", exists, err)
+	}
+	if owner, err := o.Owner(ctx); err != nil || owner != stubOwner {
+		t.Errorf("expected owner %s, got %s, %v", stubOwner.String(), owner.String(), err)
+	}
+	for key, want := range map[common.Address]bool{authorizedKey: true, unauthorizedKey: false} {
+		if authorized, err := o.IsAuthorized(ctx, key); err != nil || authorized != want {
+			t.Errorf("expected %s to be authorized %v, got %v, %v", key.String(), want, authorized, err)
+		}
+	}
+
+	missing, err := NewOracle(common.HexToAddress("0x00000000000000000000000000000000000000dd"), backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exists, err := missing.Exists(ctx); err != nil || exists {
+		t.Errorf("expected no contract at an empty address, got %v, %v", exists, err)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	backend, address := deployStubOracle(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		oracle  common.Address
+		keys    []common.Address
+		wantErr bool
+	}{
+		{"authorized key", address, []common.Address{unauthorizedKey, authorizedKey}, false},
+		{"no authorized keys", address, []common.Address{unauthorizedKey}, true},
+		{"no keys", address, nil, true},
+		{"no contract", common.HexToAddress("0x00000000000000000000000000000000000000dd"), []common.Address{authorizedKey}, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			v, err := Verify(ctx, backend, test.oracle, test.keys)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := v.Err(); (err != nil) != test.wantErr {
+				t.Errorf("expected an error %v, got %v", test.wantErr, err)
+			}
+		})
+	}
+
+	v, err := Verify(ctx, backend, common.HexToAddress("0x00000000000000000000000000000000000000dd"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !errors.Is(v.Err(), ErrNoContract) {
+		t.Errorf("expected ErrNoContract, got %v", v.Err())
+	}
+	v, err = Verify(ctx, backend, address, []common.Address{unauthorizedKey, authorizedKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(v.Keys) != 2 || v.Keys[0].Authorized || !v.Keys[1].Authorized {
+		t.Errorf("expected only the second key to be authorized, got %v, %v", v.Keys[0], v.Keys[1])
+	}
+}
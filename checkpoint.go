@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"market-sync/client"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Checkpoint records the progress of a sync, so an interrupted sync can be resumed
+// with --resume. Failed job specs aren't recorded as processed, so they're retried.
+// Every method is safe to call on a nil *Checkpoint, so it's only written when enabled.
+type Checkpoint struct {
+	NodeID    string                 `json:"nodeId"`
+	Processed map[string]SpecOutcome `json:"processed"`
+	// InProgress is the job spec being prompted for, with any name, cost and parameter changes made so far
+	InProgress *client.ChainlinkJobSpec `json:"inProgress,omitempty"`
+	UpdatedAt  time.Time                `json:"updatedAt"`
+
+	path string
+}
+
+func NewCheckpoint(path, nodeId string) *Checkpoint {
+	return &Checkpoint{NodeID: nodeId, Processed: map[string]SpecOutcome{}, path: path}
+}
+
+// LoadCheckpoint reads the checkpoint file, returning nil if there isn't one
+func LoadCheckpoint(path string) (*Checkpoint, error) {
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	c := &Checkpoint{}
+	if err := json.Unmarshal(b, c); err != nil {
+		return nil, fmt.Errorf("invalid checkpoint file %s: %v", path, err)
+	}
+	if c.Processed == nil {
+		c.Processed = map[string]SpecOutcome{}
+	}
+	c.path = path
+	return c, nil
+}
+
+// Record marks the job spec as processed, unless it failed
+func (c *Checkpoint) Record(spec *client.ChainlinkJobSpec, outcome SpecOutcome) error {
+	if c == nil || outcome == OutcomeFailed || outcome == OutcomePending {
+		return nil
+	}
+	c.Processed[spec.ID] = outcome
+	if c.InProgress != nil && c.InProgress.ID == spec.ID {
+		c.InProgress = nil
+	}
+	return c.Save()
+}
+
+// Progress saves the job spec as it's being prompted for
+func (c *Checkpoint) Progress(spec *client.ChainlinkJobSpec) error {
+	if c == nil {
+		return nil
+	}
+	c.InProgress = spec
+	return c.Save()
+}
+
+// Restore replaces the spec's name, cost and attributes with the in progress
+// spec's, if it's the same spec, returning whether it was restored
+func (c *Checkpoint) Restore(spec *client.ChainlinkJobSpec) bool {
+	if c == nil || c.InProgress == nil || c.InProgress.ID != spec.ID {
+		return false
+	}
+	spec.Name = c.InProgress.Name
+	spec.MinPayment = c.InProgress.MinPayment
+	spec.Attributes = c.InProgress.Attributes
+	return true
+}
+
+// Save replaces the file in a single rename, so it's never left partially written
+func (c *Checkpoint) Save() error {
+	c.UpdatedAt = time.Now().UTC()
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(c.path), ".market-sync-checkpoint-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		_ = tmp.Close()
+		return err
+	} else if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), c.path)
+}
+
+// Remove deletes the checkpoint file once the sync has completed
+func (c *Checkpoint) Remove() error {
+	if c == nil {
+		return nil
+	}
+	if err := os.Remove(c.path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -3,11 +3,10 @@ package client
 import (
 	"bytes"
 	"encoding/json"
-	"errors"
 	"fmt"
-	"go.uber.org/multierr"
 	"io/ioutil"
 	"net/http"
+	"time"
 )
 
 type Chainlink struct {
@@ -38,6 +37,31 @@ func (c *Chainlink) Config() (*ChainlinkConfig, error) {
 	return cfg, err
 }
 
+func (c *Chainlink) ETHKeys() (*ChainlinkETHKeys, error) {
+	k := &ChainlinkETHKeys{}
+	_, err := c.do(
+		http.MethodGet,
+		"/v2/keys/eth",
+		nil,
+		http.StatusOK,
+		k,
+	)
+	return k, err
+}
+
+// Balances returns the ETH and LINK balance of each of the node's keys
+func (c *Chainlink) Balances() (*ChainlinkETHKeys, error) {
+	b := &ChainlinkETHKeys{}
+	_, err := c.do(
+		http.MethodGet,
+		"/v2/user/balances",
+		nil,
+		http.StatusOK,
+		b,
+	)
+	return b, err
+}
+
 func (c *Chainlink) ReadSpec(id string) (*ChainlinkJobSpec, error) {
 	j := &ChainlinkJobSpec{}
 	_, err := c.do(
@@ -62,6 +86,43 @@ func (c *Chainlink) GetSpecs(page, size int) (*ChainlinkJobSpecs, error) {
 	return j, err
 }
 
+func (c *Chainlink) GetSpecRuns(id string, page, size int) (*ChainlinkJobRuns, error) {
+	r := &ChainlinkJobRuns{}
+	_, err := c.do(
+		http.MethodGet,
+		fmt.Sprintf("/v2/specs/%s/runs?page=%d&size=%d", id, page, size),
+		nil,
+		http.StatusOK,
+		r,
+	)
+	return r, err
+}
+
+// SpecRunStats aggregates up to the limit of the job's runs, paging through them from the first page
+func (c *Chainlink) SpecRunStats(id string, limit int) (*JobRunStats, error) {
+	size := 100
+	if limit < size {
+		size = limit
+	}
+	var runs []*ChainlinkJobRun
+	total := 0
+	for page := 1; len(runs) < limit; page++ {
+		r, err := c.GetSpecRuns(id, page, size)
+		if err != nil {
+			return nil, err
+		}
+		total = r.Meta.Count
+		runs = append(runs, r.Data...)
+		if len(r.Data) == 0 || page*size >= total {
+			break
+		}
+	}
+	if len(runs) > limit {
+		runs = runs[:limit]
+	}
+	return NewJobRunStats(total, runs), nil
+}
+
 func (c *Chainlink) CreateBridgeType(name, url string) error {
 	bta := ChainlinkBridgeTypeAttributes{Name: name, URL: url}
 	_, err := c.do(
@@ -115,7 +176,7 @@ func (c *Chainlink) setSessionCookie() error {
 		}
 	}
 	if c.cookie == nil {
-		return fmt.Errorf("chainlink: session cookie wasn't returned on login")
+		return fmt.Errorf("chainlink: session cookie wasn't returned on login: %w", ErrUnauthorized)
 	}
 	return nil
 }
@@ -150,32 +211,31 @@ func (c *Chainlink) do(
 		req.AddCookie(c.cookie)
 	}
 	req.Header.Set("Content-Type", "application/json")
+	start := time.Now()
 	resp, err := client.Do(req)
+	observe("chainlink", method, endpoint, resp, time.Since(start), err)
 
 	if err != nil {
-		return resp, err
-	} else if b, err := ioutil.ReadAll(resp.Body); err != nil {
-		return resp, err
+		return resp, &NetworkError{Service: "chainlink", Method: method, Endpoint: endpoint, Err: err}
+	}
+	defer resp.Body.Close()
+	if b, err := ioutil.ReadAll(resp.Body); err != nil {
+		return resp, &NetworkError{Service: "chainlink", Method: method, Endpoint: endpoint, Err: err}
 	} else if resp.StatusCode != code {
+		apiErr := &APIError{
+			Service:    "chainlink",
+			Method:     method,
+			Endpoint:   endpoint,
+			StatusCode: resp.StatusCode,
+			Expected:   code,
+		}
 		errs := ChainlinkErrors{}
-		defaultErr := fmt.Errorf(
-			"chainlink: unexpected response code, got %d, expected %d when calling %s",
-			resp.StatusCode,
-			code,
-			endpoint,
-		)
-		if err := json.Unmarshal(b, &errs); err != nil {
-			return resp, defaultErr
-		} else if len(errs.Errors) == 0 {
-			return resp, defaultErr
-		} else {
-			var merr error
-			merr = multierr.Append(merr, defaultErr)
-			for _, err := range errs.Errors {
-				merr = multierr.Append(merr, errors.New(err.Detail))
+		if err := json.Unmarshal(b, &errs); err == nil {
+			for _, e := range errs.Errors {
+				apiErr.Details = append(apiErr.Details, e.Detail)
 			}
-			return resp, merr
 		}
+		return resp, apiErr
 	} else if obj == nil {
 		return resp, nil
 	} else if err := json.Unmarshal(b, obj); err != nil {
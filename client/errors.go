@@ -0,0 +1,96 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Sentinel errors matched by the errors returned from the Chainlink and Market
+// clients with errors.Is, eg: errors.Is(err, client.ErrUnauthorized)
+var (
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrNotFound     = errors.New("not found")
+	ErrValidation   = errors.New("validation failed")
+	ErrServer       = errors.New("server error")
+	ErrNetwork      = errors.New("network error")
+)
+
+// FieldError is a validation error for a single field of the request
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (e FieldError) String() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// APIError is returned when the Chainlink node or the Market responds with an unexpected status code
+type APIError struct {
+	// Service is either chainlink or market
+	Service    string
+	Method     string
+	Endpoint   string
+	StatusCode int
+	Expected   int
+	// Details are any error messages in the response
+	Details     []string
+	FieldErrors []FieldError
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf(
+		"%s: unexpected response code, got %d, expected %d when calling %s",
+		e.Service,
+		e.StatusCode,
+		e.Expected,
+		e.Endpoint,
+	)
+	details := e.Details
+	for _, fe := range e.FieldErrors {
+		details = append(details, fe.String())
+	}
+	if len(details) > 0 {
+		msg += "; " + strings.Join(details, "; ")
+	}
+	return msg
+}
+
+// Is matches the sentinel error for the status code
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrValidation:
+		return len(e.FieldErrors) > 0 ||
+			e.StatusCode == http.StatusBadRequest ||
+			e.StatusCode == http.StatusUnprocessableEntity
+	case ErrServer:
+		return e.StatusCode >= http.StatusInternalServerError
+	}
+	return false
+}
+
+// NetworkError is returned when a request couldn't be made or its response couldn't be read
+type NetworkError struct {
+	Service  string
+	Method   string
+	Endpoint string
+	Err      error
+}
+
+func (e *NetworkError) Error() string {
+	return fmt.Sprintf("%s: %s %s failed: %v", e.Service, e.Method, e.Endpoint, e.Err)
+}
+
+func (e *NetworkError) Unwrap() error {
+	return e.Err
+}
+
+func (e *NetworkError) Is(target error) bool {
+	return target == ErrNetwork
+}
@@ -0,0 +1,47 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestAPIError_Is(t *testing.T) {
+	sentinels := []error{ErrUnauthorized, ErrNotFound, ErrValidation, ErrServer, ErrNetwork}
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"401", &APIError{StatusCode: http.StatusUnauthorized}, ErrUnauthorized},
+		{"403", &APIError{StatusCode: http.StatusForbidden}, ErrUnauthorized},
+		{"404", &APIError{StatusCode: http.StatusNotFound}, ErrNotFound},
+		{"409", &APIError{StatusCode: http.StatusConflict}, nil},
+		{"400", &APIError{StatusCode: http.StatusBadRequest}, ErrValidation},
+		{"422", &APIError{StatusCode: http.StatusUnprocessableEntity}, ErrValidation},
+		{"field errors", &APIError{StatusCode: http.StatusConflict, FieldErrors: []FieldError{{"name", "taken"}}}, ErrValidation},
+		{"500", &APIError{StatusCode: http.StatusInternalServerError}, ErrServer},
+		{"503", &APIError{StatusCode: http.StatusServiceUnavailable}, ErrServer},
+		{"network", &NetworkError{Err: errors.New("connection refused")}, ErrNetwork},
+		{"wrapped", fmt.Errorf("creating job: %w", &APIError{StatusCode: http.StatusNotFound}), ErrNotFound},
+		{"wrapped network", fmt.Errorf("listing jobs: %w", &NetworkError{Err: errors.New("timeout")}), ErrNetwork},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for _, sentinel := range sentinels {
+				if got := errors.Is(test.err, sentinel); got != (sentinel == test.want) {
+					t.Errorf("errors.Is(%q, %v) = %v", test.err, sentinel, got)
+				}
+			}
+		})
+	}
+}
+
+func TestNetworkError_Unwrap(t *testing.T) {
+	cause := errors.New("connection refused")
+	err := fmt.Errorf("syncing: %w", &NetworkError{Err: cause})
+	if !errors.Is(err, cause) {
+		t.Fatalf("expected %v to unwrap to %v", err, cause)
+	}
+}
@@ -0,0 +1,84 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+const ethDecimals = 18
+
+// Eth is an amount of ETH, stored in wei (1 ETH = 10^18 wei)
+type Eth big.Int
+
+// ParseEth parses an amount that's either given as a decimal amount of ETH,
+// eg: "0.1" or "0.1 ETH", or as whole wei, eg: "100000000000000000 wei"
+func ParseEth(s string) (*Eth, error) {
+	v := strings.TrimSpace(s)
+	lower := strings.ToLower(v)
+	isWei := false
+	switch {
+	case strings.HasSuffix(lower, "eth"):
+		v = strings.TrimSpace(v[:len(v)-len("eth")])
+	case strings.HasSuffix(lower, "wei"):
+		v = strings.TrimSpace(v[:len(v)-len("wei")])
+		isWei = true
+	}
+	if len(v) == 0 {
+		return nil, errors.New("amount is empty")
+	}
+	if strings.HasPrefix(v, "-") {
+		return nil, fmt.Errorf("invalid amount %q, must not be negative", s)
+	}
+	if isWei {
+		wei, ok := new(big.Int).SetString(v, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid amount %q, wei must be a whole number", s)
+		}
+		return (*Eth)(wei), nil
+	}
+	wei, ok := parseDecimal(v, ethDecimals)
+	if !ok {
+		return nil, fmt.Errorf("invalid amount %q, expected ETH with at most %d decimal places, eg: 0.1 ETH", s, ethDecimals)
+	}
+	return (*Eth)(wei), nil
+}
+
+func (e *Eth) ToInt() *big.Int {
+	return (*big.Int)(e)
+}
+
+func (e *Eth) Cmp(o *Eth) int {
+	return e.ToInt().Cmp(o.ToInt())
+}
+
+// String returns the amount as a decimal amount of ETH, eg: "0.1 ETH"
+func (e *Eth) String() string {
+	if e == nil {
+		return "0 ETH"
+	}
+	return formatDecimal(e.ToInt(), ethDecimals) + " ETH"
+}
+
+func (e *Eth) MarshalJSON() ([]byte, error) {
+	return json.Marshal(formatDecimal(e.ToInt(), ethDecimals))
+}
+
+// UnmarshalJSON accepts the amount as a string of ETH, as the node serialises balances
+func (e *Eth) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		s = string(b)
+	}
+	if s == "" || s == "null" {
+		return nil
+	}
+	v, err := ParseEth(s)
+	if err != nil {
+		return err
+	}
+	*e = *v
+	return nil
+}
@@ -0,0 +1,127 @@
+package client
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/ethereum/go-ethereum/common"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// numberPattern matches strings that are decimal numbers, which the node reads the same as JSON numbers
+var numberPattern = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?([eE][-+]?[0-9]+)?$`)
+
+// canonicalSpec is the content of a job spec that decides what the job does
+type canonicalSpec struct {
+	Initiators []*canonicalInitiator `json:"initiators"`
+	Tasks      []*canonicalTask      `json:"tasks"`
+}
+
+type canonicalInitiator struct {
+	Type   string      `json:"type"`
+	Params interface{} `json:"params"`
+}
+
+type canonicalTask struct {
+	Type          string      `json:"type"`
+	Confirmations uint64      `json:"confirmations"`
+	Params        interface{} `json:"params"`
+}
+
+// Canonical returns the spec's content as compact JSON, so specs that are materially
+// identical give the same bytes. The spec's ID, name, node, cost, stats and provenance
+// are left out, as is the oracle address of its runlog initiators so the same job on
+// different oracles matches. Initiator and task types are lower cased, the keys of every
+// object are sorted, and numbers, including strings that are numbers, are written in
+// full without trailing zeros.
+func (s *ChainlinkJobSpec) Canonical() ([]byte, error) {
+	c := &canonicalSpec{Initiators: []*canonicalInitiator{}, Tasks: []*canonicalTask{}}
+	for _, i := range s.Attributes.Initiators {
+		c.Initiators = append(c.Initiators, &canonicalInitiator{
+			Type:   strings.ToLower(i.Type),
+			Params: canonicalValue(initiatorParams(i)),
+		})
+	}
+	for _, t := range s.Attributes.Tasks {
+		c.Tasks = append(c.Tasks, &canonicalTask{
+			Type:          strings.ToLower(t.Type),
+			Confirmations: t.Confirmations,
+			Params:        canonicalValue(t.Params),
+		})
+	}
+	return json.Marshal(c)
+}
+
+// ContentHash returns the hex encoded SHA-256 hash of the spec's canonical content,
+// which is listed as its fingerprint
+func (s *ChainlinkJobSpec) ContentHash() (string, error) {
+	b, err := s.Canonical()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// initiatorParams returns every param of the initiator, with addresses checksummed
+// and the oracle address of a runlog initiator left out
+func initiatorParams(i *ChainlinkInitiator) map[string]interface{} {
+	params := make(map[string]interface{}, len(i.Raw))
+	for k, v := range i.Raw {
+		params[k] = v
+	}
+	if i.Address != (common.Address{}) {
+		params["address"] = i.Address.Hex()
+	}
+	if strings.ToLower(i.Type) == "runlog" {
+		delete(params, "address")
+	}
+	return params
+}
+
+func canonicalValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			m[k] = canonicalValue(e)
+		}
+		return m
+	case []interface{}:
+		a := make([]interface{}, len(v))
+		for i, e := range v {
+			a[i] = canonicalValue(e)
+		}
+		return a
+	case float64:
+		return canonicalNumber(strconv.FormatFloat(v, 'g', -1, 64))
+	case json.Number:
+		return canonicalNumber(v.String())
+	case string:
+		if numberPattern.MatchString(v) {
+			return canonicalNumber(v)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// canonicalNumber writes the number in full, eg: 1e3 and 1000.0 are both 1000
+func canonicalNumber(s string) interface{} {
+	f, _, err := big.ParseFloat(s, 10, 256, big.ToNearestEven)
+	if err != nil {
+		return s
+	}
+	text := f.Text('f', -1)
+	if strings.Contains(text, ".") {
+		text = strings.TrimRight(strings.TrimRight(text, "0"), ".")
+	}
+	if text == "-0" {
+		text = "0"
+	}
+	return json.Number(text)
+}
@@ -0,0 +1,82 @@
+package client
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestChainlinkJobSpec_ContentHash(t *testing.T) {
+	spec := func(initiators string) *ChainlinkJobSpec {
+		s := &ChainlinkJobSpec{}
+		b := `{"initiators": ` + initiators + `, "tasks": [{"type": "HttpGet", "params": {"get": "https://example.com", "times": 100}}]}`
+		if err := json.Unmarshal([]byte(b), &s.Attributes); err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+	hash := func(s *ChainlinkJobSpec) string {
+		h, err := s.ContentHash()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return h
+	}
+
+	tests := []struct {
+		name string
+		a, b string
+		same bool
+	}{
+		{
+			"runlog oracle address",
+			`[{"type": "runlog", "params": {"address": "0x1000000000000000000000000000000000000001"}}]`,
+			`[{"type": "RunLog", "params": {"address": "0x2000000000000000000000000000000000000002"}}]`,
+			true,
+		},
+		{
+			"runlog requesters",
+			`[{"type": "runlog", "params": {"requesters": ["0x3000000000000000000000000000000000000003"]}}]`,
+			`[{"type": "runlog", "params": {"requesters": ["0x4000000000000000000000000000000000000004"]}}]`,
+			false,
+		},
+		{
+			"cron schedule",
+			`[{"type": "cron", "params": {"schedule": "CRON_TZ=UTC 0 * * * *"}}]`,
+			`[{"type": "cron", "params": {"schedule": "CRON_TZ=UTC 30 * * * *"}}]`,
+			false,
+		},
+		{
+			"ethlog address",
+			`[{"type": "ethlog", "params": {"address": "0x1000000000000000000000000000000000000001"}}]`,
+			`[{"type": "ethlog", "params": {"address": "0x2000000000000000000000000000000000000002"}}]`,
+			false,
+		},
+		{
+			"address case",
+			`[{"type": "ethlog", "params": {"address": "0xabcdef0000000000000000000000000000000001"}}]`,
+			`[{"type": "ethlog", "params": {"address": "0xABCDEF0000000000000000000000000000000001"}}]`,
+			true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if same := hash(spec(test.a)) == hash(spec(test.b)); same != test.same {
+				t.Errorf("expected the fingerprints to be the same %v, got %v", test.same, same)
+			}
+		})
+	}
+}
+
+func TestChainlinkInitiator_UnmarshalJSON(t *testing.T) {
+	var i ChainlinkInitiator
+	b := `{"type": "runlog", "params": {"address": "0x1000000000000000000000000000000000000001", "requesters": ["0x2"]}}`
+	if err := json.Unmarshal([]byte(b), &i); err != nil {
+		t.Fatal(err)
+	}
+	if i.Type != "runlog" || i.Address.Hex() != "0x1000000000000000000000000000000000000001" {
+		t.Errorf("expected a runlog initiator for the oracle, got %s for %s", i.Type, i.Address.Hex())
+	}
+	if len(i.Raw) != 2 || i.Raw["requesters"] == nil {
+		t.Errorf("expected every param to be kept, got %v", i.Raw)
+	}
+}
@@ -0,0 +1,134 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+const linkDecimals = 18
+
+// Link is an amount of LINK, stored in juels (1 LINK = 10^18 juels)
+type Link big.Int
+
+func NewLink(juels int64) *Link {
+	return (*Link)(big.NewInt(juels))
+}
+
+// ParseLink parses an amount that's either given as whole juels, eg: "100000000000000000",
+// or as a decimal amount of LINK, eg: "0.1 LINK" or "0.1"
+func ParseLink(s string) (*Link, error) {
+	v := strings.TrimSpace(s)
+	lower := strings.ToLower(v)
+	isLink := strings.Contains(v, ".")
+	switch {
+	case strings.HasSuffix(lower, "link"):
+		v = strings.TrimSpace(v[:len(v)-len("link")])
+		isLink = true
+	case strings.HasSuffix(lower, "juels"):
+		v = strings.TrimSpace(v[:len(v)-len("juels")])
+		if isLink {
+			return nil, fmt.Errorf("invalid amount %q, juels must be a whole number", s)
+		}
+	}
+	if len(v) == 0 {
+		return nil, errors.New("amount is empty")
+	}
+	if strings.HasPrefix(v, "-") {
+		return nil, fmt.Errorf("invalid amount %q, must not be negative", s)
+	}
+	if !isLink {
+		juels, ok := new(big.Int).SetString(v, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid amount %q, expected juels or LINK, eg: 0.1 LINK", s)
+		}
+		return (*Link)(juels), nil
+	}
+
+	juels, ok := parseDecimal(v, linkDecimals)
+	if !ok {
+		return nil, fmt.Errorf("invalid amount %q, expected juels or LINK with at most %d decimal places, eg: 0.1 LINK", s, linkDecimals)
+	}
+	return (*Link)(juels), nil
+}
+
+// parseDecimal parses a decimal amount, eg: "0.1", into the smallest unit of a
+// token with the given number of decimal places
+func parseDecimal(v string, decimals int) (*big.Int, bool) {
+	parts := strings.SplitN(v, ".", 2)
+	whole, fraction := parts[0], ""
+	if len(parts) == 2 {
+		fraction = parts[1]
+	}
+	if len(fraction) > decimals {
+		return nil, false
+	}
+	if len(whole) == 0 {
+		whole = "0"
+	}
+	fraction += strings.Repeat("0", decimals-len(fraction))
+	return new(big.Int).SetString(whole+fraction, 10)
+}
+
+// formatDecimal formats an amount in a token's smallest unit as a decimal amount
+func formatDecimal(i *big.Int, decimals int) string {
+	unit := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	q, r := new(big.Int).QuoRem(i, unit, new(big.Int))
+	s := q.String()
+	if r.Sign() != 0 {
+		fraction := r.String()
+		fraction = strings.Repeat("0", decimals-len(fraction)) + fraction
+		s += "." + strings.TrimRight(fraction, "0")
+	}
+	return s
+}
+
+func (l *Link) ToInt() *big.Int {
+	return (*big.Int)(l)
+}
+
+func (l *Link) Cmp(o *Link) int {
+	return l.ToInt().Cmp(o.ToInt())
+}
+
+// String returns the amount in juels
+func (l *Link) String() string {
+	if l == nil {
+		return "0"
+	}
+	return l.ToInt().String()
+}
+
+// LinkString returns the amount as a decimal amount of LINK, eg: "0.1 LINK"
+func (l *Link) LinkString() string {
+	return formatDecimal(l.ToInt(), linkDecimals) + " LINK"
+}
+
+// Display returns both representations, eg: "0.1 LINK (100000000000000000 juels)"
+func (l *Link) Display() string {
+	return fmt.Sprintf("%s (%s juels)", l.LinkString(), l.String())
+}
+
+func (l *Link) MarshalJSON() ([]byte, error) {
+	return json.Marshal(l.String())
+}
+
+// UnmarshalJSON accepts the amount either as a string or as a number, the node
+// serialises it as a string but older versions used numbers
+func (l *Link) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		s = string(b)
+	}
+	if s == "" || s == "null" {
+		return nil
+	}
+	v, err := ParseLink(s)
+	if err != nil {
+		return err
+	}
+	*l = *v
+	return nil
+}
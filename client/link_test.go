@@ -0,0 +1,72 @@
+package client
+
+import (
+	"testing"
+)
+
+func TestParseLink(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"100000000000000000", "100000000000000000", false},
+		{"100000000000000000 juels", "100000000000000000", false},
+		{"0.1 LINK", "100000000000000000", false},
+		{"0.1 link", "100000000000000000", false},
+		{"0.1", "100000000000000000", false},
+		{".5", "500000000000000000", false},
+		{"1 LINK", "1000000000000000000", false},
+		{"12.345", "12345000000000000000", false},
+		{" 2.5LINK ", "2500000000000000000", false},
+		{"0.000000000000000001 LINK", "1", false},
+		{"0", "0", false},
+		{"0.0000000000000000001 LINK", "", true},
+		{"-1", "", true},
+		{"-0.1 LINK", "", true},
+		{"0.1 juels", "", true},
+		{"1e18", "", true},
+		{"1.2.3", "", true},
+		{"LINK", "", true},
+		{"", "", true},
+	}
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			got, err := ParseLink(test.input)
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %s", got)
+				}
+				return
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if got.String() != test.want {
+				t.Errorf("expected %s juels, got %s", test.want, got)
+			}
+		})
+	}
+}
+
+func TestLink_LinkString(t *testing.T) {
+	tests := []struct {
+		juels string
+		want  string
+	}{
+		{"0", "0 LINK"},
+		{"1", "0.000000000000000001 LINK"},
+		{"100000000000000000", "0.1 LINK"},
+		{"12345000000000000000", "12.345 LINK"},
+	}
+	for _, test := range tests {
+		t.Run(test.juels, func(t *testing.T) {
+			l, err := ParseLink(test.juels)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := l.LinkString(); got != test.want {
+				t.Errorf("expected %s, got %s", test.want, got)
+			}
+		})
+	}
+}
@@ -3,20 +3,20 @@ package client
 import (
 	"bytes"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"github.com/ethereum/go-ethereum/common"
 	uuid "github.com/satori/go.uuid"
-	"go.uber.org/multierr"
 	"io/ioutil"
 	"net/http"
-	"strings"
+	"time"
 )
 
 const (
 	MarketURL               = "https://market.link/v1"
 	MarketAccessKeyIDHeader = "x-access-key-id"
 	MarketSecretKeyHeader   = "x-secret-key"
+	// MarketIdempotencyKeyHeader is sent when creating a job, so the Market can ignore repeated requests
+	MarketIdempotencyKeyHeader = "Idempotency-Key"
 )
 
 type Market struct {
@@ -50,13 +50,16 @@ func (m *Market) SetActiveUser() error {
 	return err
 }
 
+// CreateJob creates the job spec's Market job, sending an idempotency key that's the
+// same for every attempt to create it on the same node
 func (m *Market) CreateJob(spec *ChainlinkJobSpec) (*MarketCreated, error) {
 	c := &MarketCreated{}
 	spec.Initiators = spec.Attributes.Initiators
 	spec.Tasks = spec.Attributes.Tasks
-	_, err := m.do(
+	_, err := m.doWithHeaders(
 		http.MethodPost,
 		"/jobs/spec",
+		map[string]string{MarketIdempotencyKeyHeader: IdempotencyKey(spec)},
 		spec,
 		http.StatusCreated,
 		&c,
@@ -64,11 +67,32 @@ func (m *Market) CreateJob(spec *ChainlinkJobSpec) (*MarketCreated, error) {
 	return c, err
 }
 
+// IdempotencyKey is the same for every attempt to create a job spec's job on a Market node
+func IdempotencyKey(spec *ChainlinkJobSpec) string {
+	var node string
+	if spec.NodeID != nil {
+		node = spec.NodeID.String()
+	}
+	return uuid.NewV5(uuid.NamespaceURL, fmt.Sprintf("market-sync:%s:%s", node, NormaliseJobID(spec.ID))).String()
+}
+
 func (m *Market) Jobs(nodeId uuid.UUID, page, size int) (*MarketJobPage, error) {
 	j := &MarketJobPage{}
 	_, err := m.do(
 		http.MethodGet,
-		fmt.Sprintf("/nodes?page=%d&size=%d&nodeId=%s", page, size, nodeId.String()),
+		fmt.Sprintf("/jobs?page=%d&size=%d&nodeId=%s", page, size, nodeId.String()),
+		nil,
+		http.StatusOK,
+		j,
+	)
+	return j, err
+}
+
+func (m *Market) Job(jobId uuid.UUID) (*MarketJob, error) {
+	j := &MarketJob{}
+	_, err := m.do(
+		http.MethodGet,
+		fmt.Sprintf("/jobs/%s", jobId.String()),
 		nil,
 		http.StatusOK,
 		j,
@@ -82,7 +106,7 @@ func (m *Market) JobExists(jobNodeId string, networkId int) (bool, error) {
 		http.MethodGet,
 		fmt.Sprintf(
 			"/jobs?nodeJobId[]=%s&networkId=%d",
-			strings.Replace(jobNodeId, "-", "", -1),
+			NormaliseJobID(jobNodeId),
 			networkId,
 		),
 		nil,
@@ -106,10 +130,47 @@ func (m *Market) NodeByOracleAddress(oracle *common.Address, networkId int) (*Ma
 		http.StatusOK,
 		n,
 	)
-	if len(n.Data) == 0 {
-		return nil, errors.New("node not found")
+	if err != nil {
+		return nil, err
+	} else if len(n.Data) == 0 {
+		return nil, fmt.Errorf("market: node for oracle %s on network %d %w", oracle.String(), networkId, ErrNotFound)
 	}
-	return n.Data[0], err
+	return n.Data[0], nil
+}
+
+func (m *Market) Node(nodeId uuid.UUID) (*MarketNode, error) {
+	n := &MarketNode{}
+	_, err := m.do(
+		http.MethodGet,
+		fmt.Sprintf("/nodes/%s", nodeId.String()),
+		nil,
+		http.StatusOK,
+		n,
+	)
+	return n, err
+}
+
+func (m *Market) CreateNode(node *MarketNodeRequest) (*MarketCreated, error) {
+	c := &MarketCreated{}
+	_, err := m.do(
+		http.MethodPost,
+		"/nodes",
+		node,
+		http.StatusCreated,
+		c,
+	)
+	return c, err
+}
+
+func (m *Market) UpdateNode(nodeId uuid.UUID, node *MarketNodeRequest) error {
+	_, err := m.do(
+		http.MethodPut,
+		fmt.Sprintf("/nodes/%s", nodeId.String()),
+		node,
+		http.StatusOK,
+		nil,
+	)
+	return err
 }
 
 func (m *Market) do(
@@ -118,6 +179,17 @@ func (m *Market) do(
 	body interface{},
 	code int,
 	obj interface{},
+) (*http.Response, error) {
+	return m.doWithHeaders(method, endpoint, nil, body, code, obj)
+}
+
+func (m *Market) doWithHeaders(
+	method string,
+	endpoint string,
+	headers map[string]string,
+	body interface{},
+	code int,
+	obj interface{},
 ) (*http.Response, error) {
 	var b []byte
 	if body != nil {
@@ -141,34 +213,35 @@ func (m *Market) do(
 	req.Header.Set(MarketAccessKeyIDHeader, m.accessKey)
 	req.Header.Set(MarketSecretKeyHeader, m.secretKey)
 	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	start := time.Now()
 	resp, err := client.Do(req)
+	observe("market", method, endpoint, resp, time.Since(start), err)
 
 	if err != nil {
-		return resp, err
-	} else if b, err := ioutil.ReadAll(resp.Body); err != nil {
-		return resp, err
+		return resp, &NetworkError{Service: "market", Method: method, Endpoint: endpoint, Err: err}
+	}
+	defer resp.Body.Close()
+	if b, err := ioutil.ReadAll(resp.Body); err != nil {
+		return resp, &NetworkError{Service: "market", Method: method, Endpoint: endpoint, Err: err}
 	} else if resp.StatusCode != code {
+		apiErr := &APIError{
+			Service:    "market",
+			Method:     method,
+			Endpoint:   endpoint,
+			StatusCode: resp.StatusCode,
+			Expected:   code,
+		}
 		e := MarketError{}
-		defaultErr := fmt.Errorf(
-			"market: unexpected response code, got %d, expected %d when calling %s",
-			resp.StatusCode,
-			code,
-			endpoint,
-		)
-		if err := json.Unmarshal(b, &e); err != nil {
-			return resp, defaultErr
-		} else if len(e.Error) == 0 {
-			return resp, defaultErr
-		} else if len(e.InputErrors) > 0 {
-			var merr error
-			merr = multierr.Append(merr, defaultErr)
-			for _, err := range e.InputErrors {
-				merr = multierr.Append(merr, fmt.Errorf("%s: %s", err.Field, err.Error))
+		if err := json.Unmarshal(b, &e); err == nil && len(e.Error) > 0 {
+			apiErr.Details = []string{e.Error}
+			for _, ie := range e.InputErrors {
+				apiErr.FieldErrors = append(apiErr.FieldErrors, FieldError{Field: ie.Field, Message: ie.Error})
 			}
-			return resp, merr
-		} else {
-			return resp, errors.New(e.Error)
 		}
+		return resp, apiErr
 	} else if obj == nil {
 		return resp, nil
 	} else if err := json.Unmarshal(b, obj); err != nil {
@@ -1,8 +1,11 @@
 package client
 
 import (
+	"encoding/json"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/satori/go.uuid"
+	"strings"
+	"time"
 )
 
 type ChainlinkClientConfig struct {
@@ -54,8 +57,35 @@ type ChainlinkInitiator struct {
 	ChainlinkInitiatorParams `json:"params,omitempty"`
 }
 
+// ChainlinkInitiatorParams are the initiator's params the sync reads, with every
+// param the node reports kept in Raw
 type ChainlinkInitiatorParams struct {
 	Address common.Address `json:"address,omitempty" gorm:"index"`
+
+	Raw map[string]interface{} `json:"-"`
+}
+
+func (i *ChainlinkInitiator) UnmarshalJSON(b []byte) error {
+	var v struct {
+		Type   string          `json:"type"`
+		Params json.RawMessage `json:"params"`
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	*i = ChainlinkInitiator{Type: v.Type}
+	if len(v.Params) == 0 || string(v.Params) == "null" {
+		return nil
+	} else if err := json.Unmarshal(v.Params, &i.ChainlinkInitiatorParams); err != nil {
+		return err
+	}
+	return json.Unmarshal(v.Params, &i.Raw)
+}
+
+// NormaliseJobID strips the dashes the Market removes from node job IDs, so a
+// job spec's ID can be matched against the node job IDs of Market jobs
+func NormaliseJobID(id string) string {
+	return strings.ToLower(strings.Replace(id, "-", "", -1))
 }
 
 type ChainlinkJobSpec struct {
@@ -66,6 +96,19 @@ type ChainlinkJobSpec struct {
 	MinPayment string                     `json:"minPayment,omitempty"`
 	Initiators []*ChainlinkInitiator      `json:"initiators,omitempty"`
 	Tasks      []*ChainlinkTaskSpec       `json:"tasks,omitempty"`
+	Stats      *JobRunStats               `json:"stats,omitempty"`
+	// Fingerprint is the hash of the spec's canonical content, set as it's published
+	Fingerprint string `json:"fingerprint,omitempty"`
+	// Provenance is the operator's signature over the published spec, if it's signed
+	Provenance *Provenance `json:"provenance,omitempty"`
+}
+
+// Provenance is an EIP-191 personal signature over the canonical payload of a
+// published job spec, so consumers of the listing can verify who published it
+type Provenance struct {
+	Payload   string         `json:"payload"`
+	Signature string         `json:"signature"`
+	Signer    common.Address `json:"signer"`
 }
 
 type ChainlinkJobSpecAttributes struct {
@@ -88,20 +131,104 @@ type ChainlinkJobSpecCreated struct {
 	Data *ChainlinkJobSpec `json:"data"`
 }
 
+type ChainlinkJobRuns struct {
+	Data []*ChainlinkJobRun `json:"data"`
+	Meta ChainlinkMeta      `json:"meta"`
+}
+
+type ChainlinkJobRun struct {
+	ID         string                    `json:"id"`
+	Attributes ChainlinkJobRunAttributes `json:"attributes"`
+}
+
+type ChainlinkJobRunAttributes struct {
+	JobID      string     `json:"jobId"`
+	Status     string     `json:"status"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	FinishedAt *time.Time `json:"finishedAt"`
+}
+
 type ChainlinkConfig struct {
 	Data struct {
-		Attributes struct {
-			ETHChainID            int             `json:"ethChainId"`
-		} `json:"attributes"`
+		Attributes ChainlinkConfigAttributes `json:"attributes"`
+	} `json:"data"`
+}
+
+// ChainlinkConfigAttributes are the parts of the node's config the sync uses, with
+// every attribute the node reports kept in Raw
+type ChainlinkConfigAttributes struct {
+	AccountAddress         string `json:"accountAddress"`
+	ETHChainID             int    `json:"ethChainId"`
+	LinkContractAddress    string `json:"linkContractAddress"`
+	MinimumContractPayment *Link  `json:"minimumContractPayment"`
+	OracleContractAddress  string `json:"oracleContractAddress"`
+	// Version is only reported by some node versions
+	Version string `json:"version"`
+
+	Raw map[string]interface{} `json:"-"`
+}
+
+func (a *ChainlinkConfigAttributes) UnmarshalJSON(b []byte) error {
+	type attributes ChainlinkConfigAttributes
+	var attrs attributes
+	if err := json.Unmarshal(b, &attrs); err != nil {
+		return err
+	} else if err := json.Unmarshal(b, &attrs.Raw); err != nil {
+		return err
+	}
+	*a = ChainlinkConfigAttributes(attrs)
+	return nil
+}
+
+// OracleAddress returns the node's ORACLE_CONTRACT_ADDRESS, if it's set
+func (a *ChainlinkConfigAttributes) OracleAddress() (common.Address, bool) {
+	if !common.IsHexAddress(a.OracleContractAddress) {
+		return common.Address{}, false
+	}
+	address := common.HexToAddress(a.OracleContractAddress)
+	return address, address != (common.Address{})
+}
+
+// ChainlinkETHKeys are the node's Ethereum accounts, which fulfil requests, as
+// listed by both the keys and balances endpoints
+type ChainlinkETHKeys struct {
+	Data []struct {
+		ID         string          `json:"id"`
+		Attributes ChainlinkETHKey `json:"attributes"`
 	} `json:"data"`
 }
 
+type ChainlinkETHKey struct {
+	Address     common.Address `json:"address"`
+	ETHBalance  *Eth           `json:"ethBalance"`
+	LinkBalance *Link          `json:"linkBalance"`
+}
+
+// Addresses returns the address of every key
+func (k *ChainlinkETHKeys) Addresses() []common.Address {
+	var addresses []common.Address
+	for _, d := range k.Data {
+		address := d.Attributes.Address
+		if address == (common.Address{}) && common.IsHexAddress(d.ID) {
+			address = common.HexToAddress(d.ID)
+		}
+		addresses = append(addresses, address)
+	}
+	return addresses
+}
+
 type MarketJob struct {
+	ID        uuid.UUID     `json:"id"`
 	Name      string        `json:"name"`
 	NodeID    uuid.UUID     `json:"nodeId"`
 	NodeJobID string        `form:"nodeJobId,omitempty"`
 	Tasks     []*MarketTask `form:"tasks,omitempty"`
 	Cost      string        `json:"cost"`
+	// Fingerprint is the hash of the spec's canonical content when it was published,
+	// only listed for jobs published by versions that set it
+	Fingerprint string `json:"fingerprint,omitempty"`
+	// Provenance is only listed for jobs published with a signature
+	Provenance *Provenance `json:"provenance,omitempty"`
 }
 
 type MarketTask struct {
@@ -134,12 +261,25 @@ type MarketUser struct {
 
 type MarketNode struct {
 	ID            uuid.UUID         `json:"id"`
+	Name          string            `json:"name"`
+	Description   string            `json:"description"`
+	Website       string            `json:"website"`
 	OracleAddress common.Address    `json:"oracleAddress"`
 	Network       MarketNodeNetwork `json:"network"`
 }
 
+// MarketNodeRequest creates or updates a node on the Market
+type MarketNodeRequest struct {
+	Name          string         `json:"name"`
+	Description   string         `json:"description,omitempty"`
+	Website       string         `json:"website,omitempty"`
+	OracleAddress common.Address `json:"oracleAddress"`
+	NetworkID     int            `json:"networkId"`
+}
+
 type MarketNodeNetwork struct {
-	ID int `json:"id"`
+	ID   int    `json:"id"`
+	Name string `json:"name"`
 }
 
 type MarketNodePage struct {
@@ -0,0 +1,48 @@
+package client
+
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// RequestObserver is called after every request to the Chainlink node or the Market,
+// with the response code being 0 if no response was received
+type RequestObserver func(service, method, route string, code int, duration time.Duration, err error)
+
+var (
+	observer  RequestObserver
+	idMatcher = regexp.MustCompile(`^(0x)?[0-9a-fA-F-]{16,}$`)
+)
+
+// ObserveRequests sets the observer for every client
+func ObserveRequests(o RequestObserver) {
+	observer = o
+}
+
+func observe(service, method, endpoint string, resp *http.Response, duration time.Duration, err error) {
+	if observer == nil {
+		return
+	}
+	code := 0
+	if resp != nil {
+		code = resp.StatusCode
+	}
+	observer(service, method, route(endpoint), code, duration, err)
+}
+
+// route strips the query and any IDs from the endpoint, so requests to the same route are grouped
+func route(endpoint string) string {
+	if u, err := url.Parse(endpoint); err == nil {
+		endpoint = u.Path
+	}
+	parts := strings.Split(endpoint, "/")
+	for i, p := range parts {
+		if idMatcher.MatchString(p) {
+			parts[i] = ":id"
+		}
+	}
+	return strings.Join(parts, "/")
+}
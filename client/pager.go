@@ -0,0 +1,201 @@
+package client
+
+import (
+	"errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	// DefaultPageSize is the page size used by the iterators when it isn't set
+	DefaultPageSize = 50
+
+	// maxScans is how many times a listing is read while it's changing before
+	// the iterators settle for every item seen across the reads
+	maxScans = 3
+)
+
+// pageFunc reads a single page, returning the ID of each item along with the total number of items
+type pageFunc func(page, size int) (ids []string, items []interface{}, total int, err error)
+
+// pager reads every page of a listing up front, so items added or deleted while
+// iterating can't shift items between pages and cause them to be skipped or repeated
+type pager struct {
+	size  int
+	fetch pageFunc
+
+	loaded bool
+	ids    []string
+	items  map[string]interface{}
+	total  int
+	index  int
+	err    error
+}
+
+func newPager(size int, fetch pageFunc) *pager {
+	if size <= 0 {
+		size = DefaultPageSize
+	}
+	return &pager{size: size, fetch: fetch}
+}
+
+func (p *pager) next() bool {
+	if !p.loaded {
+		p.loaded = true
+		p.err = p.load()
+	}
+	if p.err != nil || p.index >= len(p.ids) {
+		return false
+	}
+	p.index++
+	return true
+}
+
+func (p *pager) item() interface{} {
+	if p.index == 0 || p.index > len(p.ids) {
+		return nil
+	}
+	return p.items[p.ids[p.index-1]]
+}
+
+// load reads the listing until a read is stable, where the total is the same before
+// and after the read and matches the number of unique items read. If the listing
+// never settles, every item seen across the reads is kept.
+func (p *pager) load() error {
+	p.items = map[string]interface{}{}
+	for scan := 0; scan < maxScans; scan++ {
+		ids, items, stable, err := p.scan()
+		if err != nil {
+			return err
+		}
+		if stable {
+			p.ids, p.items = ids, items
+			p.total = len(ids)
+			return nil
+		}
+		for _, id := range ids {
+			if _, ok := p.items[id]; !ok {
+				p.ids = append(p.ids, id)
+			}
+			p.items[id] = items[id]
+		}
+	}
+	p.total = len(p.ids)
+	return nil
+}
+
+func (p *pager) scan() ([]string, map[string]interface{}, bool, error) {
+	var ids []string
+	items := map[string]interface{}{}
+	start := -1
+	var total, seen int
+	for page := 1; ; page++ {
+		pageIds, pageItems, pageTotal, err := p.fetch(page, p.size)
+		if err != nil {
+			return nil, nil, false, err
+		} else if len(pageIds) != len(pageItems) {
+			return nil, nil, false, errors.New("pager: page has a different number of IDs and items")
+		}
+		if start < 0 {
+			start = pageTotal
+		}
+		total = pageTotal
+		seen += len(pageIds)
+		for i, id := range pageIds {
+			if _, ok := items[id]; !ok {
+				ids = append(ids, id)
+			}
+			items[id] = pageItems[i]
+		}
+		// a short page isn't the last, as the API may cap the page size below the one asked for
+		if len(pageIds) == 0 || seen >= pageTotal {
+			break
+		}
+	}
+	return ids, items, start == total && len(ids) == total, nil
+}
+
+// SpecIterator iterates over every job spec on a Chainlink node
+//
+//	it := client.NewSpecIterator(chainlink.GetSpecs, 50)
+//	for it.Next() {
+//		spec := it.Spec()
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type SpecIterator struct {
+	p *pager
+}
+
+func NewSpecIterator(getSpecs func(page, size int) (*ChainlinkJobSpecs, error), size int) *SpecIterator {
+	return &SpecIterator{p: newPager(size, func(page, size int) ([]string, []interface{}, int, error) {
+		resp, err := getSpecs(page, size)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		ids := make([]string, len(resp.Data))
+		items := make([]interface{}, len(resp.Data))
+		for i, spec := range resp.Data {
+			ids[i], items[i] = NormaliseJobID(spec.ID), spec
+		}
+		return ids, items, resp.Meta.Count, nil
+	})}
+}
+
+func (it *SpecIterator) Next() bool {
+	return it.p.next()
+}
+
+func (it *SpecIterator) Spec() *ChainlinkJobSpec {
+	spec, _ := it.p.item().(*ChainlinkJobSpec)
+	return spec
+}
+
+// Total is the number of unique job specs, once Next has been called
+func (it *SpecIterator) Total() int {
+	return it.p.total
+}
+
+func (it *SpecIterator) Err() error {
+	return it.p.err
+}
+
+// JobIterator iterates over every job listed for a node on the Market
+//
+//	it := client.NewJobIterator(market.Jobs, nodeId, 50)
+type JobIterator struct {
+	p *pager
+}
+
+func NewJobIterator(jobs func(nodeId uuid.UUID, page, size int) (*MarketJobPage, error), nodeId uuid.UUID, size int) *JobIterator {
+	return &JobIterator{p: newPager(size, func(page, size int) ([]string, []interface{}, int, error) {
+		resp, err := jobs(nodeId, page, size)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		ids := make([]string, len(resp.Data))
+		items := make([]interface{}, len(resp.Data))
+		for i, job := range resp.Data {
+			ids[i], items[i] = job.ID.String(), job
+		}
+		return ids, items, resp.TotalCount, nil
+	})}
+}
+
+func (it *JobIterator) Next() bool {
+	return it.p.next()
+}
+
+func (it *JobIterator) Job() *MarketJob {
+	job, _ := it.p.item().(*MarketJob)
+	return job
+}
+
+// Total is the number of unique jobs, once Next has been called
+func (it *JobIterator) Total() int {
+	return it.p.total
+}
+
+func (it *JobIterator) Err() error {
+	return it.p.err
+}
@@ -0,0 +1,142 @@
+package client
+
+import (
+	"fmt"
+	"testing"
+)
+
+// listing serves ids in pages of at most max items, however many are asked for
+func listing(ids []string, max int) pageFunc {
+	return func(page, size int) ([]string, []interface{}, int, error) {
+		if size > max {
+			size = max
+		}
+		var pageIds []string
+		var items []interface{}
+		for i := (page - 1) * size; i < len(ids) && i < page*size; i++ {
+			pageIds = append(pageIds, ids[i])
+			items = append(items, ids[i])
+		}
+		return pageIds, items, len(ids), nil
+	}
+}
+
+func TestPager_ReadsEveryPage(t *testing.T) {
+	var ids []string
+	for i := 0; i < 7; i++ {
+		ids = append(ids, fmt.Sprintf("spec%d", i))
+	}
+	tests := []struct {
+		name string
+		size int
+		max  int
+	}{
+		{"full pages", 3, 3},
+		{"exact pages", 7, 7},
+		{"capped page size", 5, 2},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := newPager(test.size, listing(ids, test.max))
+			var got []string
+			for p.next() {
+				got = append(got, p.item().(string))
+			}
+			if p.err != nil {
+				t.Fatal(p.err)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(ids) {
+				t.Errorf("expected %v, got %v", ids, got)
+			}
+		})
+	}
+}
+
+func TestPager_StopsOnEmptyPage(t *testing.T) {
+	var reads int
+	p := newPager(2, func(page, size int) ([]string, []interface{}, int, error) {
+		reads++
+		if page > 1 {
+			return nil, nil, 5, nil
+		}
+		return []string{"a", "b"}, []interface{}{"a", "b"}, 5, nil
+	})
+	for p.next() {
+	}
+	if p.err != nil {
+		t.Fatal(p.err)
+	}
+	if p.total != 2 {
+		t.Errorf("expected the 2 items read, got %d", p.total)
+	}
+	if reads != 2*maxScans {
+		t.Errorf("expected each scan to stop at the empty page, got %d reads", reads)
+	}
+}
+
+// changingListing serves ids in pages, calling change after every page is read so
+// items can be added and removed between pages
+func changingListing(ids *[]string, change func(reads int)) pageFunc {
+	reads := 0
+	return func(page, size int) ([]string, []interface{}, int, error) {
+		pageIds, items, total, err := listing(*ids, size)(page, size)
+		reads++
+		change(reads)
+		return pageIds, items, total, err
+	}
+}
+
+func TestPager_ListingChangesBetweenPages(t *testing.T) {
+	tests := []struct {
+		name string
+		// changes is how many reads the listing keeps changing for
+		changes int
+	}{
+		{"settles", 1},
+		{"settles after a few reads", 4},
+		{"never settles", 100},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ids := []string{"a", "b", "c", "d", "e", "f", "g"}
+			surviving := map[string]bool{"b": true, "d": true, "e": true, "f": true, "g": true}
+			removals := []string{"a", "c"}
+			added := 0
+			p := newPager(2, changingListing(&ids, func(reads int) {
+				if reads > test.changes {
+					return
+				}
+				// inserting at the front and removing an earlier item shift the
+				// remaining items across the page boundaries
+				added++
+				ids = append([]string{fmt.Sprintf("new%d", added)}, ids...)
+				if len(removals) > 0 {
+					for i, id := range ids {
+						if id == removals[0] {
+							ids = append(ids[:i:i], ids[i+1:]...)
+							break
+						}
+					}
+					removals = removals[1:]
+				}
+			}))
+			yielded := map[string]int{}
+			for p.next() {
+				yielded[p.item().(string)]++
+			}
+			if p.err != nil {
+				t.Fatal(p.err)
+			}
+			for id, count := range yielded {
+				if count != 1 {
+					t.Errorf("expected %s to be yielded once, got %d", id, count)
+				}
+			}
+			for id := range surviving {
+				if yielded[id] != 1 {
+					t.Errorf("expected surviving item %s to be yielded once, got %d", id, yielded[id])
+				}
+			}
+		})
+	}
+}
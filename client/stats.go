@@ -0,0 +1,57 @@
+package client
+
+import (
+	"time"
+)
+
+const (
+	RunStatusCompleted = "completed"
+	RunStatusErrored   = "errored"
+)
+
+// JobRunStats is the reliability of a job, from a sample of its most recent runs
+type JobRunStats struct {
+	Total            int        `json:"total"`
+	Sampled          int        `json:"sampled"`
+	Completed        int        `json:"completed"`
+	Errored          int        `json:"errored"`
+	InProgress       int        `json:"inProgress"`
+	SuccessRate      float64    `json:"successRate"`
+	AverageLatencyMs int64      `json:"averageLatencyMs"`
+	LastRunAt        *time.Time `json:"lastRunAt,omitempty"`
+}
+
+// NewJobRunStats aggregates the runs, where the total is the count of every run the job has had
+func NewJobRunStats(total int, runs []*ChainlinkJobRun) *JobRunStats {
+	s := &JobRunStats{Total: total, Sampled: len(runs)}
+	var latency time.Duration
+	var finished int
+	for _, r := range runs {
+		switch r.Attributes.Status {
+		case RunStatusCompleted:
+			s.Completed++
+		case RunStatusErrored:
+			s.Errored++
+		default:
+			s.InProgress++
+		}
+		if r.Attributes.FinishedAt != nil && r.Attributes.Status == RunStatusCompleted {
+			latency += r.Attributes.FinishedAt.Sub(r.Attributes.CreatedAt)
+			finished++
+		}
+		if createdAt := r.Attributes.CreatedAt; s.LastRunAt == nil || createdAt.After(*s.LastRunAt) {
+			s.LastRunAt = &createdAt
+		}
+	}
+	if done := s.Completed + s.Errored; done > 0 {
+		s.SuccessRate = float64(s.Completed) / float64(done)
+	}
+	if finished > 0 {
+		s.AverageLatencyMs = (latency / time.Duration(finished)).Milliseconds()
+	}
+	return s
+}
+
+func (s *JobRunStats) AverageLatency() time.Duration {
+	return time.Duration(s.AverageLatencyMs) * time.Millisecond
+}
@@ -0,0 +1,430 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/fatih/color"
+	"io/ioutil"
+	"market-sync/client"
+	"market-sync/prompt"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	editActionEdit   = "Edit a parameter"
+	editActionAdd    = "Add a parameter"
+	editActionRemove = "Remove a parameter"
+	editActionEditor = "Open the job spec in $EDITOR"
+	editActionUndo   = "Undo the last change"
+	editActionDone   = "Done"
+)
+
+// specEditor edits a job spec's tasks in place, keeping a snapshot of the
+// spec's attributes before every change so they can be undone
+type specEditor struct {
+	prompter prompt.Prompter
+	spec     *client.ChainlinkJobSpec
+	history  [][]byte
+	// onChange is called after every change to the spec, if set
+	onChange func(spec *client.ChainlinkJobSpec)
+}
+
+func newSpecEditor(prompter prompt.Prompter, spec *client.ChainlinkJobSpec) *specEditor {
+	return &specEditor{prompter: prompter, spec: spec}
+}
+
+func (e *specEditor) Run() error {
+	if len(e.spec.Attributes.Tasks) == 0 {
+		return errors.New("no job spec tasks")
+	}
+	for {
+		actions := []string{editActionEdit, editActionAdd, editActionRemove, editActionEditor}
+		if len(e.history) > 0 {
+			actions = append(actions, editActionUndo)
+		}
+		actions = append(actions, editActionDone)
+
+		action, err := e.prompter.Select("Select an action", actions)
+		if err != nil {
+			return err
+		}
+		switch action {
+		case editActionEdit:
+			err = e.editParam()
+		case editActionAdd:
+			err = e.addParam()
+		case editActionRemove:
+			err = e.removeParam()
+		case editActionEditor:
+			err = e.openEditor()
+		case editActionUndo:
+			err = e.undo()
+		case editActionDone:
+			return nil
+		}
+		if err != nil {
+			displayError(err)
+		} else if e.onChange != nil {
+			e.onChange(e.spec)
+		}
+	}
+}
+
+func (e *specEditor) selectTask() (*client.ChainlinkTaskSpec, error) {
+	var options []string
+	for i, t := range e.spec.Attributes.Tasks {
+		options = append(options, fmt.Sprintf("%d: %s", i, t.Type))
+	}
+	answer, err := e.prompter.Select("Select task", options)
+	if err != nil {
+		return nil, err
+	}
+	for i, o := range options {
+		if o == answer {
+			return e.spec.Attributes.Tasks[i], nil
+		}
+	}
+	return nil, fmt.Errorf("unknown task %s", answer)
+}
+
+// selectParam selects any parameter of the task, including values nested within objects and arrays
+func (e *specEditor) selectParam(t *client.ChainlinkTaskSpec) ([]string, error) {
+	paths := map[string][]string{}
+	walkParams(t.Params, nil, func(path []string) {
+		paths[strings.Join(path, ".")] = path
+	})
+	if len(paths) == 0 {
+		return nil, errors.New("no parameters for this task")
+	}
+	var options []string
+	for k := range paths {
+		options = append(options, k)
+	}
+	sort.Strings(options)
+	answer, err := e.prompter.Select("Select parameter", options)
+	if err != nil {
+		return nil, err
+	}
+	return paths[answer], nil
+}
+
+func (e *specEditor) editParam() error {
+	t, err := e.selectTask()
+	if err != nil {
+		return err
+	}
+	path, err := e.selectParam(t)
+	if err != nil {
+		return err
+	}
+	current, _ := getParam(t.Params, path)
+	value, err := e.prompter.Ask("Enter new value", &prompt.Options{
+		Default: formatParam(current),
+		Validate: func(s string) error {
+			_, err := parseParamAs(s, current)
+			return err
+		},
+	})
+	if err != nil {
+		return err
+	}
+	v, _ := parseParamAs(value, current)
+	e.snapshot()
+	return setParam(t, path, v)
+}
+
+func (e *specEditor) addParam() error {
+	t, err := e.selectTask()
+	if err != nil {
+		return err
+	}
+	key, err := e.prompter.Ask("Enter parameter name, use . for nested values", &prompt.Options{
+		Required: true,
+	})
+	if err != nil {
+		return err
+	}
+	value, err := e.prompter.Ask("Enter value (JSON or text)", nil)
+	if err != nil {
+		return err
+	}
+	e.snapshot()
+	return setParam(t, strings.Split(key, "."), parseParam(value))
+}
+
+func (e *specEditor) removeParam() error {
+	t, err := e.selectTask()
+	if err != nil {
+		return err
+	}
+	path, err := e.selectParam(t)
+	if err != nil {
+		return err
+	}
+	e.snapshot()
+	return deleteParam(t, path)
+}
+
+// openEditor opens the job spec's initiators and tasks in $EDITOR, only
+// keeping the changes if the saved file is a valid job spec
+func (e *specEditor) openEditor() error {
+	b, err := json.MarshalIndent(e.spec.Attributes, "", "  ")
+	if err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile("", "market-sync-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(b); err != nil {
+		return err
+	} else if err := f.Close(); err != nil {
+		return err
+	}
+
+	for {
+		if err := runEditor(f.Name()); err != nil {
+			return err
+		}
+		b, err := ioutil.ReadFile(f.Name())
+		if err != nil {
+			return err
+		}
+		attrs, err := parseJobSpecAttributes(b)
+		if err == nil {
+			e.snapshot()
+			e.spec.Attributes = *attrs
+			return nil
+		}
+		displayError(err)
+		if ok, err := e.prompter.Confirm("Re-open the editor to fix the job spec?", true); err != nil {
+			return err
+		} else if !ok {
+			color.Yellow("Discarded changes made in the editor")
+			return nil
+		}
+	}
+}
+
+func (e *specEditor) snapshot() {
+	b, _ := json.Marshal(e.spec.Attributes)
+	e.history = append(e.history, b)
+}
+
+func (e *specEditor) undo() error {
+	if len(e.history) == 0 {
+		return errors.New("nothing to undo")
+	}
+	last := e.history[len(e.history)-1]
+	e.history = e.history[:len(e.history)-1]
+	attrs := client.ChainlinkJobSpecAttributes{}
+	if err := json.Unmarshal(last, &attrs); err != nil {
+		return err
+	}
+	e.spec.Attributes = attrs
+	return nil
+}
+
+func runEditor(path string) error {
+	editor := strings.Fields(os.Getenv("EDITOR"))
+	if len(editor) == 0 {
+		editor = []string{"vi"}
+	}
+	cmd := exec.Command(editor[0], append(editor[1:], path)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+func parseJobSpecAttributes(b []byte) (*client.ChainlinkJobSpecAttributes, error) {
+	attrs := &client.ChainlinkJobSpecAttributes{}
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.DisallowUnknownFields()
+	if err := d.Decode(attrs); err != nil {
+		return nil, fmt.Errorf("invalid job spec: %v", err)
+	}
+	if len(attrs.Initiators) == 0 {
+		return nil, errors.New("invalid job spec: no initiators")
+	} else if len(attrs.Tasks) == 0 {
+		return nil, errors.New("invalid job spec: no tasks")
+	}
+	for i, in := range attrs.Initiators {
+		if in == nil || len(in.Type) == 0 {
+			return nil, fmt.Errorf("invalid job spec: initiator %d has no type", i)
+		}
+	}
+	for i, t := range attrs.Tasks {
+		if t == nil || len(t.Type) == 0 {
+			return nil, fmt.Errorf("invalid job spec: task %d has no type", i)
+		}
+	}
+	return attrs, nil
+}
+
+// walkParams calls fn with the path of every value in the params, leaves and
+// containers alike, so whole objects and arrays can be edited as well as their elements
+func walkParams(v interface{}, path []string, fn func([]string)) {
+	switch p := v.(type) {
+	case map[string]interface{}:
+		for k, c := range p {
+			child := append(append([]string{}, path...), k)
+			fn(child)
+			walkParams(c, child, fn)
+		}
+	case []interface{}:
+		for i, c := range p {
+			child := append(append([]string{}, path...), strconv.Itoa(i))
+			fn(child)
+			walkParams(c, child, fn)
+		}
+	}
+}
+
+func getParam(v interface{}, path []string) (interface{}, bool) {
+	for _, key := range path {
+		switch p := v.(type) {
+		case map[string]interface{}:
+			var ok bool
+			if v, ok = p[key]; !ok {
+				return nil, false
+			}
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(p) {
+				return nil, false
+			}
+			v = p[i]
+		default:
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+func setParam(t *client.ChainlinkTaskSpec, path []string, value interface{}) error {
+	if t.Params == nil {
+		t.Params = map[string]interface{}{}
+	}
+	v, err := setPath(t.Params, path, value)
+	if err != nil {
+		return err
+	}
+	t.Params = v.(map[string]interface{})
+	return nil
+}
+
+// setPath sets the value at the path, creating any missing objects along the way
+func setPath(v interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	key := path[0]
+	switch p := v.(type) {
+	case map[string]interface{}:
+		child, err := setPath(p[key], path[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		p[key] = child
+		return p, nil
+	case []interface{}:
+		i, err := strconv.Atoi(key)
+		if err != nil || i < 0 || i > len(p) {
+			return nil, fmt.Errorf("invalid array index %s", key)
+		} else if i == len(p) {
+			p = append(p, nil)
+		}
+		child, err := setPath(p[i], path[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		p[i] = child
+		return p, nil
+	case nil:
+		return setPath(map[string]interface{}{}, path, value)
+	default:
+		return nil, fmt.Errorf("can't set %s, parent value isn't an object or array", key)
+	}
+}
+
+func deleteParam(t *client.ChainlinkTaskSpec, path []string) error {
+	parent, ok := getParam(t.Params, path[:len(path)-1])
+	if !ok {
+		return fmt.Errorf("parameter %s not found", strings.Join(path, "."))
+	}
+	key := path[len(path)-1]
+	switch p := parent.(type) {
+	case map[string]interface{}:
+		delete(p, key)
+		return nil
+	case []interface{}:
+		i, err := strconv.Atoi(key)
+		if err != nil || i < 0 || i >= len(p) {
+			return fmt.Errorf("invalid array index %s", key)
+		}
+		_, err = setPath(t.Params, path[:len(path)-1], append(p[:i:i], p[i+1:]...))
+		return err
+	default:
+		return fmt.Errorf("parameter %s not found", strings.Join(path, "."))
+	}
+}
+
+// formatParam shows strings as they are, and anything else as JSON
+func formatParam(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+// parseParam parses a new value as JSON, falling back to text
+func parseParam(s string) interface{} {
+	var v interface{}
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		return s
+	}
+	return v
+}
+
+// parseParamAs parses a value so it keeps the same type as the value it replaces
+func parseParamAs(s string, current interface{}) (interface{}, error) {
+	switch current.(type) {
+	case string:
+		return s, nil
+	case float64:
+		f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		if err != nil {
+			return nil, errors.New("value must be a number")
+		}
+		return f, nil
+	case bool:
+		b, err := strconv.ParseBool(strings.TrimSpace(s))
+		if err != nil {
+			return nil, errors.New("value must be true or false")
+		}
+		return b, nil
+	case []interface{}:
+		var a []interface{}
+		if err := json.Unmarshal([]byte(s), &a); err != nil {
+			return nil, errors.New("value must be a JSON array")
+		}
+		return a, nil
+	case map[string]interface{}:
+		var m map[string]interface{}
+		if err := json.Unmarshal([]byte(s), &m); err != nil {
+			return nil, errors.New("value must be a JSON object")
+		}
+		return m, nil
+	default:
+		return parseParam(s), nil
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"encoding/json"
+	"market-sync/client"
+	"reflect"
+	"testing"
+)
+
+// taskWithParams returns a task with the params decoded from JSON, as they are read from the node
+func taskWithParams(t *testing.T, params string) *client.ChainlinkTaskSpec {
+	task := &client.ChainlinkTaskSpec{Type: "httpget"}
+	if err := json.Unmarshal([]byte(params), &task.Params); err != nil {
+		t.Fatal(err)
+	}
+	return task
+}
+
+func TestSetParam(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    []string
+		value   interface{}
+		want    string
+		wantErr bool
+	}{
+		{"top level", []string{"get"}, "https://example.org", `{"get":"https://example.org","path":["data","price"],"headers":{"a":["1"]}}`, false},
+		{"new key", []string{"times"}, float64(100), `{"get":"https://example.com","path":["data","price"],"headers":{"a":["1"]},"times":100}`, false},
+		{"array element", []string{"path", "1"}, "usd", `{"get":"https://example.com","path":["data","usd"],"headers":{"a":["1"]}}`, false},
+		{"array append", []string{"path", "2"}, "usd", `{"get":"https://example.com","path":["data","price","usd"],"headers":{"a":["1"]}}`, false},
+		{"nested", []string{"headers", "a", "0"}, "2", `{"get":"https://example.com","path":["data","price"],"headers":{"a":["2"]}}`, false},
+		{"creates objects", []string{"extra", "b", "c"}, true, `{"get":"https://example.com","path":["data","price"],"headers":{"a":["1"]},"extra":{"b":{"c":true}}}`, false},
+		{"array index past the end", []string{"path", "3"}, "usd", "", true},
+		{"array index not a number", []string{"path", "x"}, "usd", "", true},
+		{"parent isn't a container", []string{"get", "host"}, "example.org", "", true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			task := taskWithParams(t, `{"get":"https://example.com","path":["data","price"],"headers":{"a":["1"]}}`)
+			err := setParam(task, test.path, test.value)
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %v", task.Params)
+				}
+				return
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			checkParams(t, task, test.want)
+		})
+	}
+}
+
+func TestDeleteParam(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    []string
+		want    string
+		wantErr bool
+	}{
+		{"top level", []string{"get"}, `{"path":["data","price","usd"],"headers":{"a":["1","2"]}}`, false},
+		{"first array element", []string{"path", "0"}, `{"get":"https://example.com","path":["price","usd"],"headers":{"a":["1","2"]}}`, false},
+		{"middle array element", []string{"path", "1"}, `{"get":"https://example.com","path":["data","usd"],"headers":{"a":["1","2"]}}`, false},
+		{"last array element", []string{"path", "2"}, `{"get":"https://example.com","path":["data","price"],"headers":{"a":["1","2"]}}`, false},
+		{"nested array element", []string{"headers", "a", "0"}, `{"get":"https://example.com","path":["data","price","usd"],"headers":{"a":["2"]}}`, false},
+		{"nested object", []string{"headers", "a"}, `{"get":"https://example.com","path":["data","price","usd"],"headers":{}}`, false},
+		{"array index out of range", []string{"path", "3"}, "", true},
+		{"missing parent", []string{"body", "a"}, "", true},
+		{"parent isn't a container", []string{"get", "host"}, "", true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			task := taskWithParams(t, `{"get":"https://example.com","path":["data","price","usd"],"headers":{"a":["1","2"]}}`)
+			err := deleteParam(task, test.path)
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %v", task.Params)
+				}
+				return
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			checkParams(t, task, test.want)
+		})
+	}
+}
+
+func TestParseParamAs(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		current interface{}
+		want    interface{}
+		wantErr bool
+	}{
+		{"string stays a string", "100", "https://example.com", "100", false},
+		{"string keeps spaces", " true ", "yes", " true ", false},
+		{"number", " 1.5 ", float64(100), float64(1.5), false},
+		{"number from text", "many", float64(100), nil, true},
+		{"bool", "false", true, false, false},
+		{"bool from text", "nope", true, nil, true},
+		{"array", `["data","usd"]`, []interface{}{"data"}, []interface{}{"data", "usd"}, false},
+		{"array from text", "data.usd", []interface{}{"data"}, nil, true},
+		{"object", `{"a":1}`, map[string]interface{}{}, map[string]interface{}{"a": float64(1)}, false},
+		{"object from text", "a=1", map[string]interface{}{}, nil, true},
+		{"new value as JSON", "100", nil, float64(100), false},
+		{"new value as text", "https://example.com", nil, "https://example.com", false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := parseParamAs(test.input, test.current)
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %v", got)
+				}
+				return
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("expected %#v, got %#v", test.want, got)
+			}
+		})
+	}
+}
+
+// checkParams compares the task's params to the expected JSON
+func checkParams(t *testing.T, task *client.ChainlinkTaskSpec, want string) {
+	t.Helper()
+	var expected map[string]interface{}
+	if err := json.Unmarshal([]byte(want), &expected); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(task.Params, expected) {
+		got, _ := json.Marshal(task.Params)
+		t.Errorf("expected %s, got %s", want, got)
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"market-sync/client"
+	"market-sync/provenance"
+	"os"
+)
+
+// Exit codes, so scripts running the sync can tell why it failed
+const (
+	ExitCodeOK          = 0
+	ExitCodeError       = 1
+	ExitCodeAuth        = 2
+	ExitCodeValidation  = 3
+	ExitCodeNetwork     = 4
+	ExitCodePartialSync = 5
+)
+
+// ErrPartialSync is matched by a PartialSyncError with errors.Is
+var ErrPartialSync = errors.New("partial sync")
+
+// PartialSyncError is returned when a sync finishes with some job specs failing to be published
+type PartialSyncError struct {
+	Synced int
+	Failed int
+}
+
+func (e *PartialSyncError) Error() string {
+	return fmt.Sprintf("%d job specs failed to be published, %d were published", e.Failed, e.Synced)
+}
+
+func (e *PartialSyncError) Is(target error) bool {
+	return target == ErrPartialSync
+}
+
+// exitCode returns the exit code for the error, with the first match in the order below
+func exitCode(err error) int {
+	switch {
+	case err == nil:
+		return ExitCodeOK
+	case errors.Is(err, client.ErrUnauthorized):
+		return ExitCodeAuth
+	case errors.Is(err, client.ErrValidation),
+		errors.Is(err, provenance.ErrUnsigned),
+		errors.Is(err, provenance.ErrInvalidSignature):
+		return ExitCodeValidation
+	case errors.Is(err, client.ErrNetwork):
+		return ExitCodeNetwork
+	case errors.Is(err, ErrPartialSync):
+		return ExitCodePartialSync
+	default:
+		return ExitCodeError
+	}
+}
+
+func exit(err error) {
+	if err == nil {
+		os.Exit(ExitCodeOK)
+	}
+	displayError(err)
+	os.Exit(exitCode(err))
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"market-sync/client"
+	"market-sync/provenance"
+	"net/http"
+	"testing"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, ExitCodeOK},
+		{"401", &client.APIError{StatusCode: http.StatusUnauthorized}, ExitCodeAuth},
+		{"403", &client.APIError{StatusCode: http.StatusForbidden}, ExitCodeAuth},
+		{"404", &client.APIError{StatusCode: http.StatusNotFound}, ExitCodeError},
+		{"409", &client.APIError{StatusCode: http.StatusConflict}, ExitCodeError},
+		{"422", &client.APIError{StatusCode: http.StatusUnprocessableEntity}, ExitCodeValidation},
+		{"500", &client.APIError{StatusCode: http.StatusInternalServerError}, ExitCodeError},
+		{"network", &client.NetworkError{Err: errors.New("connection refused")}, ExitCodeNetwork},
+		{"unsigned", provenance.ErrUnsigned, ExitCodeValidation},
+		{"invalid signature", fmt.Errorf("%w: signed by someone else", provenance.ErrInvalidSignature), ExitCodeValidation},
+		{"partial sync", &PartialSyncError{Synced: 2, Failed: 1}, ExitCodePartialSync},
+		{"wrapped 401", fmt.Errorf("listing jobs: %w", &client.APIError{StatusCode: http.StatusUnauthorized}), ExitCodeAuth},
+		{"wrapped network", fmt.Errorf("listing jobs: %w", &client.NetworkError{Err: errors.New("timeout")}), ExitCodeNetwork},
+		{"wrapped partial sync", fmt.Errorf("watching: %w", &PartialSyncError{Failed: 1}), ExitCodePartialSync},
+		{"other", errors.New("something went wrong"), ExitCodeError},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := exitCode(test.err); got != test.want {
+				t.Fatalf("expected exit code %d, got %d", test.want, got)
+			}
+		})
+	}
+}
+
+func TestPartialSyncError_Is(t *testing.T) {
+	err := fmt.Errorf("syncing: %w", &PartialSyncError{Synced: 2, Failed: 1})
+	if !errors.Is(err, ErrPartialSync) {
+		t.Fatalf("expected %v to match %v", err, ErrPartialSync)
+	} else if errors.Is(err, client.ErrServer) {
+		t.Fatalf("expected %v not to match %v", err, client.ErrServer)
+	}
+}
@@ -5,6 +5,7 @@ go 1.13
 require (
 	github.com/ethereum/go-ethereum v1.9.9
 	github.com/fatih/color v1.3.0
+	github.com/prometheus/client_golang v1.3.0
 	github.com/satori/go.uuid v1.2.0
 	github.com/spf13/cobra v0.0.5
 	github.com/spf13/pflag v1.0.3
@@ -12,4 +13,5 @@ require (
 	github.com/tcnksm/go-input v0.0.0-20180404061846-548a7d7a8ee8
 	github.com/tidwall/pretty v1.0.0
 	go.uber.org/multierr v1.4.0
+	golang.org/x/crypto v0.0.0-20190510104115-cbcb75029529
 )
@@ -2,12 +2,16 @@ package main
 
 import (
 	"fmt"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
-	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 	"github.com/tcnksm/go-input"
-	"github.com/ethereum/go-ethereum/common"
+	"market-sync/client"
+	"market-sync/notify"
+	"market-sync/prompt"
+	"market-sync/provenance"
+	"market-sync/syncer"
 	"os"
 	"strings"
 )
@@ -19,6 +23,46 @@ const (
 	ChainlinkOracleAddressFlag = "chainlink-oracle-address"
 	MarketAccessKeyFlag        = "market-access-key"
 	marketSecretKeyFlag        = "market-secret-key"
+	PricingRulesFlag           = "pricing-rules"
+	PricingStrategyFlag        = "pricing-strategy"
+	PricingFlatFlag            = "pricing-flat"
+	PricingMultiplierFlag      = "pricing-multiplier"
+	NameTemplateFlag           = "name-template"
+	TUIFlag                    = "tui"
+	MetricsAddrFlag            = "metrics-addr"
+	NotifyWebhookURLFlag       = "notify-webhook-url"
+	NotifySlackURLFlag         = "notify-slack-url"
+	NotifySMTPAddrFlag         = "notify-smtp-addr"
+	NotifySMTPUsernameFlag     = "notify-smtp-username"
+	NotifySMTPPasswordFlag     = "notify-smtp-password"
+	NotifyEmailFromFlag        = "notify-email-from"
+	NotifyEmailToFlag          = "notify-email-to"
+	AnswersFileFlag            = "answers-file"
+	AnswerRulesFlag            = "answer-rules"
+	ContinueOnErrorFlag        = "continue-on-error"
+	CheckpointFileFlag         = "checkpoint-file"
+	ResumeFlag                 = "resume"
+	PageSizeFlag               = "page-size"
+	NetworksFileFlag           = "networks-file"
+	ETHRPCURLFlag              = "eth-rpc-url"
+	MinETHBalanceFlag          = "min-eth-balance"
+	RefuseLowBalanceFlag       = "refuse-low-balance"
+	SigningKeystoreFlag        = "signing-keystore"
+	SigningPasswordFlag        = "signing-password"
+)
+
+var (
+	// nodeFlags are required by any command that reads from the Chainlink node
+	nodeFlags = []string{
+		ChainlinkEmailFlag,
+		ChainlinkPasswordFlag,
+		ChainlinkURLFlag,
+	}
+	// marketFlags are required by any command that uses the Market
+	marketFlags = []string{
+		MarketAccessKeyFlag,
+		marketSecretKeyFlag,
+	}
 )
 
 func generateCmd() *cobra.Command {
@@ -27,49 +71,89 @@ func generateCmd() *cobra.Command {
 		Args: cobra.MaximumNArgs(0),
 		Long: `A LinkPool tool to sync a Chainlink node against the Market
 All flags can be set as environment variables, eg: NODE_URL, NODE_PASSWORD`,
-		Run: run,
+		Run:              run,
+		PersistentPreRun: bindFlags,
 	}
 
 	viper.AutomaticEnv()
 	viper.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
-	newcmd.Flags().StringP(ChainlinkEmailFlag, "e", "", "chainlink node email")
-	newcmd.Flags().StringP(ChainlinkPasswordFlag, "p", "", "chainlink node password")
-	newcmd.Flags().StringP(ChainlinkURLFlag, "u", "", "chainlink node url")
-	newcmd.Flags().StringP(ChainlinkOracleAddressFlag, "o", "", "chainlink oracle address")
-	newcmd.Flags().StringP(MarketAccessKeyFlag, "a", "", "market access key")
-	newcmd.Flags().StringP(marketSecretKeyFlag, "s", "", "market secret key")
-
-	_ = newcmd.MarkFlagRequired(ChainlinkEmailFlag)
-	_ = newcmd.MarkFlagRequired(ChainlinkPasswordFlag)
-	_ = newcmd.MarkFlagRequired(ChainlinkURLFlag)
-	_ = newcmd.MarkFlagRequired(ChainlinkOracleAddressFlag)
-	_ = newcmd.MarkFlagRequired(MarketAccessKeyFlag)
-	_ = newcmd.MarkFlagRequired(marketSecretKeyFlag)
-	presetRequiredFlags(newcmd)
+	newcmd.PersistentFlags().StringP(ChainlinkEmailFlag, "e", "", "chainlink node email")
+	newcmd.PersistentFlags().StringP(ChainlinkPasswordFlag, "p", "", "chainlink node password")
+	newcmd.PersistentFlags().StringP(ChainlinkURLFlag, "u", "", "chainlink node url")
+	newcmd.PersistentFlags().StringP(ChainlinkOracleAddressFlag, "o", "", "chainlink oracle address, defaults to the node's ORACLE_CONTRACT_ADDRESS")
+	newcmd.PersistentFlags().StringP(MarketAccessKeyFlag, "a", "", "market access key")
+	newcmd.PersistentFlags().StringP(marketSecretKeyFlag, "s", "", "market secret key")
+	newcmd.PersistentFlags().String(NotifyWebhookURLFlag, "", "url to post sync events to as json")
+	newcmd.PersistentFlags().String(NotifySlackURLFlag, "", "slack compatible incoming webhook url to post sync events to")
+	newcmd.PersistentFlags().String(NotifySMTPAddrFlag, "", "smtp server to email sync events through, eg: smtp.example.com:587")
+	newcmd.PersistentFlags().String(NotifySMTPUsernameFlag, "", "smtp username")
+	newcmd.PersistentFlags().String(NotifySMTPPasswordFlag, "", "smtp password")
+	newcmd.PersistentFlags().String(NotifyEmailFromFlag, "", "address sync event emails are sent from")
+	newcmd.PersistentFlags().StringSlice(NotifyEmailToFlag, nil, "addresses sync event emails are sent to")
+	newcmd.PersistentFlags().Int(PageSizeFlag, client.DefaultPageSize, "how many job specs or jobs are read per request when listing them")
+	newcmd.PersistentFlags().String(NetworksFileFlag, "", "networks file (json) mapping chain IDs to Market network IDs, added to the defaults")
+	newcmd.PersistentFlags().String(ETHRPCURLFlag, "", "ethereum rpc url the oracle contract is verified through before syncing")
+	newcmd.PersistentFlags().String(SigningKeystoreFlag, "", "keystore file of the operator key published job specs are signed with")
+	newcmd.PersistentFlags().String(SigningPasswordFlag, "", "password of the signing keystore")
+	newcmd.PersistentFlags().String(AnswersFileFlag, "", "file of answers to every prompt, one per line, instead of prompting")
+	newcmd.PersistentFlags().String(AnswerRulesFlag, "", "rules file (json) answering prompts matching each rule's question")
+	addSyncFlags(newcmd)
+	addMetricsFlag(newcmd)
+
+	newcmd.AddCommand(generateStatusCmd())
+	newcmd.AddCommand(generateWatchCmd())
+	newcmd.AddCommand(generateProposeCmd())
+	newcmd.AddCommand(generateApproveCmd())
+	newcmd.AddCommand(generateQueueCmd())
+	newcmd.AddCommand(generateNodeCmd())
+	newcmd.AddCommand(generateVerifyCmd())
 	return newcmd
 }
 
-func presetRequiredFlags(cmd *cobra.Command) {
+// addSyncFlags adds the flags for commands that prompt for job specs to be synced
+func addSyncFlags(cmd *cobra.Command) {
+	cmd.Flags().String(PricingRulesFlag, "", "pricing rules file (json) used to cost jobs")
+	cmd.Flags().String(PricingStrategyFlag, "", "pricing strategy: flat, task, initiator or multiplier")
+	cmd.Flags().String(PricingFlatFlag, "", "flat price for every job, eg: 0.1 LINK")
+	cmd.Flags().String(PricingMultiplierFlag, "", "multiplier over the job spec's minimum payment, eg: 1.5")
+	cmd.Flags().String(NameTemplateFlag, "", "template for default job names, eg: {{.FirstHttpHost}}-{{.ResultType}}")
+	cmd.Flags().Bool(TUIFlag, false, "review every unsynced job spec in a full screen terminal UI")
+	cmd.Flags().Bool(ContinueOnErrorFlag, false, "carry on to the next job spec when one fails, rather than stopping or prompting to retry")
+	cmd.Flags().String(CheckpointFileFlag, "market-sync-checkpoint.json", "file the sync's progress is saved to, set empty to disable")
+	cmd.Flags().Bool(ResumeFlag, false, "continue the sync saved in the checkpoint file")
+	cmd.Flags().String(MinETHBalanceFlag, "", "warn before publishing if the node's fulfillment account has less ETH than this, eg: 0.5 ETH")
+	cmd.Flags().Bool(RefuseLowBalanceFlag, false, "refuse to publish, rather than warn, if the fulfillment account is below the minimum ETH balance")
+}
+
+// addMetricsFlag adds the metrics flag to the long running commands
+func addMetricsFlag(cmd *cobra.Command) {
+	cmd.Flags().String(MetricsAddrFlag, "", "address to serve prometheus metrics on, eg: :9090")
+}
+
+// bindFlags binds the flags of the command being run, so flags shared by multiple
+// commands are read from the command being run
+func bindFlags(cmd *cobra.Command, _ []string) {
 	_ = viper.BindPFlags(cmd.Flags())
-	cmd.Flags().VisitAll(func(f *pflag.Flag) {
-		if viper.IsSet(f.Name) && viper.GetString(f.Name) != "" {
-			_ = cmd.Flags().Set(f.Name, viper.GetString(f.Name))
+}
+
+// requireFlags exits if any of the flags aren't set, either as a flag or environment variable
+func requireFlags(names ...string) {
+	var missing []string
+	for _, name := range names {
+		if len(viper.GetString(name)) == 0 {
+			missing = append(missing, name)
 		}
-	})
+	}
+	if len(missing) > 0 {
+		exit(fmt.Errorf(`required flag(s) "%s" not set`, strings.Join(missing, `", "`)))
+	}
 }
 
 func run(_ *cobra.Command, _ []string) {
 	yellow := color.New(color.FgYellow).SprintFunc()
 	color.Blue("Starting the Market Sync CLI")
-	a, err := NewApplication(&Config{
-		UI:                     input.DefaultUI(),
-		ChainlinkEmail:         viper.GetString(ChainlinkEmailFlag),
-		ChainlinkPassword:      viper.GetString(ChainlinkPasswordFlag),
-		ChainlinkURL:           viper.GetString(ChainlinkURLFlag),
-		ChainlinkOracleAddress: parseOracleAddress(viper.GetString(ChainlinkOracleAddressFlag)),
-		MarketAccessKey:        viper.GetString(MarketAccessKeyFlag),
-		MarketSecretKey:        viper.GetString(marketSecretKeyFlag),
-	})
+	requireFlags(append(nodeFlags, marketFlags...)...)
+	a, err := NewApplication(serveMetrics(newSyncConfig(&Config{})))
 	if err != nil {
 		exit(err)
 	}
@@ -79,16 +163,159 @@ func run(_ *cobra.Command, _ []string) {
 	if err != nil {
 		exit(err)
 	}
-	fmt.Printf("%s %s\n", yellow("Market Node ID:"), node.ID.String())
+	a.printNodeDetails()
+	if node != nil {
+		fmt.Printf("%s %s\n", yellow("Market Node ID:"), node.ID.String())
+	}
 
-	if err := a.SyncJobSpecs(node.ID, node.Network.ID); err != nil {
-		exit(err)
+	syncErr := a.SyncJobSpecs(node)
+	if summary := a.Summary(); summary != nil {
+		_ = summary.Write(os.Stdout)
+	}
+	if syncErr != nil {
+		exit(syncErr)
 	}
 
 	color.Blue("Market Sync Complete")
 	exit(nil)
 }
 
+// newSyncConfig sets the config of commands that prompt for job specs to be synced from the flags
+func newSyncConfig(config *Config) *Config {
+	pricer, err := pricerFromFlags()
+	if err != nil {
+		exit(err)
+	}
+	config.Pricer = pricer
+	config.NameTemplate = viper.GetString(NameTemplateFlag)
+	config.TUI = viper.GetBool(TUIFlag)
+	config.ContinueOnError = viper.GetBool(ContinueOnErrorFlag)
+	config.CheckpointPath = viper.GetString(CheckpointFileFlag)
+	config.Resume = viper.GetBool(ResumeFlag)
+	if min := viper.GetString(MinETHBalanceFlag); len(min) > 0 {
+		balance, err := client.ParseEth(min)
+		if err != nil {
+			exit(fmt.Errorf("invalid %s: %w", MinETHBalanceFlag, err))
+		}
+		config.MinETHBalance = balance
+	}
+	config.RefuseLowBalance = viper.GetBool(RefuseLowBalanceFlag)
+	return newConfig(config)
+}
+
+// newConfig sets the connection details every command shares from the flags
+func newConfig(config *Config) *Config {
+	prompter, err := prompterFromFlags()
+	if err != nil {
+		exit(err)
+	}
+	config.Prompter = prompter
+	config.ChainlinkEmail = viper.GetString(ChainlinkEmailFlag)
+	config.ChainlinkPassword = viper.GetString(ChainlinkPasswordFlag)
+	config.ChainlinkURL = viper.GetString(ChainlinkURLFlag)
+	config.ChainlinkOracleAddress = parseOracleAddress(viper.GetString(ChainlinkOracleAddressFlag))
+	config.MarketAccessKey = viper.GetString(MarketAccessKeyFlag)
+	config.MarketSecretKey = viper.GetString(marketSecretKeyFlag)
+	config.ETHRPCURL = viper.GetString(ETHRPCURLFlag)
+	if path := viper.GetString(SigningKeystoreFlag); len(path) > 0 {
+		signer, err := provenance.LoadSigner(path, viper.GetString(SigningPasswordFlag))
+		if err != nil {
+			exit(err)
+		}
+		config.Signer = signer
+	}
+	config.PageSize = viper.GetInt(PageSizeFlag)
+	if path := viper.GetString(NetworksFileFlag); len(path) > 0 {
+		networks, err := syncer.LoadNetworks(path)
+		if err != nil {
+			exit(err)
+		}
+		config.Networks = networks
+	}
+	config.Notifier = notifierFromFlags()
+	return config
+}
+
+// serveMetrics starts serving metrics if the metrics flag is set, which only the
+// long running commands do
+func serveMetrics(config *Config) *Config {
+	if addr := viper.GetString(MetricsAddrFlag); len(addr) > 0 {
+		config.Metrics = NewMetrics()
+		if err := config.Metrics.Serve(addr); err != nil {
+			exit(err)
+		}
+	}
+	return config
+}
+
+// prompterFromFlags answers prompts from the answers or rules file if given,
+// otherwise prompting in the terminal
+func prompterFromFlags() (prompt.Prompter, error) {
+	answers, rules := viper.GetString(AnswersFileFlag), viper.GetString(AnswerRulesFlag)
+	switch {
+	case len(answers) > 0 && len(rules) > 0:
+		return nil, fmt.Errorf("only one of %s and %s can be set", AnswersFileFlag, AnswerRulesFlag)
+	case len(answers) > 0:
+		return prompt.LoadScripted(answers, os.Stdout)
+	case len(rules) > 0:
+		return prompt.LoadRules(rules, os.Stdout)
+	default:
+		return prompt.NewTerminal(input.DefaultUI()), nil
+	}
+}
+
+func notifierFromFlags() notify.Notifier {
+	var n notify.Notifier
+	if url := viper.GetString(NotifyWebhookURLFlag); len(url) > 0 {
+		n = append(n, notify.NewWebhookSink(url))
+	}
+	if url := viper.GetString(NotifySlackURLFlag); len(url) > 0 {
+		n = append(n, notify.NewSlackSink(url))
+	}
+	if addr := viper.GetString(NotifySMTPAddrFlag); len(addr) > 0 {
+		n = append(n, &notify.EmailSink{
+			Addr:     addr,
+			Username: viper.GetString(NotifySMTPUsernameFlag),
+			Password: viper.GetString(NotifySMTPPasswordFlag),
+			From:     viper.GetString(NotifyEmailFromFlag),
+			To:       viper.GetStringSlice(NotifyEmailToFlag),
+		})
+	}
+	return n
+}
+
+// pricerFromFlags loads the pricing rules file if given, with any pricing flags
+// overriding what's set in the file
+func pricerFromFlags() (Pricer, error) {
+	rules := &PricingRules{}
+	if path := viper.GetString(PricingRulesFlag); len(path) > 0 {
+		var err error
+		if rules, err = LoadPricingRules(path); err != nil {
+			return nil, err
+		}
+	}
+	if flat := viper.GetString(PricingFlatFlag); len(flat) > 0 {
+		price, err := client.ParseLink(flat)
+		if err != nil {
+			return nil, err
+		}
+		rules.Flat = price
+		if len(rules.Strategy) == 0 {
+			rules.Strategy = PricingStrategyFlat
+		}
+	}
+	if multiplier := viper.GetString(PricingMultiplierFlag); len(multiplier) > 0 {
+		rules.Multiplier = multiplier
+		if len(rules.Strategy) == 0 {
+			rules.Strategy = PricingStrategyMultiplier
+		}
+	}
+	if strategy := viper.GetString(PricingStrategyFlag); len(strategy) > 0 {
+		rules.Strategy = strategy
+	}
+	return NewPricer(rules)
+}
+
 func parseOracleAddress(address string) common.Address {
 	return common.HexToAddress(address)
 }
@@ -103,11 +330,3 @@ func displayError(err error) {
 	color.Red("Error:")
 	fmt.Printf("%s\n\n", err.Error())
 }
-
-func exit(err error) {
-	if err == nil {
-		os.Exit(0)
-	}
-	displayError(err)
-	os.Exit(1)
-}
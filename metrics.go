@@ -0,0 +1,153 @@
+package main
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"market-sync/client"
+	"net"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// Metrics are the Prometheus metrics exposed when running with --metrics-addr.
+// Every method is safe to call on a nil *Metrics, so they're only recorded when enabled.
+type Metrics struct {
+	registry        *prometheus.Registry
+	specsSeen       prometheus.Gauge
+	specsUnsynced   prometheus.Gauge
+	specsSynced     prometheus.Counter
+	specsSkipped    prometheus.Counter
+	specsFailed     prometheus.Counter
+	requests        *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+	lastReconcile   prometheus.Gauge
+	ready           int32
+}
+
+func NewMetrics() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		specsSeen: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "market_sync_specs_seen",
+			Help: "Number of job specs on the node at the last reconcile",
+		}),
+		specsUnsynced: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "market_sync_specs_unsynced",
+			Help: "Number of job specs on the node not on the Market at the last reconcile",
+		}),
+		specsSynced: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "market_sync_specs_synced_total",
+			Help: "Number of job specs published to the Market",
+		}),
+		specsSkipped: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "market_sync_specs_skipped_total",
+			Help: "Number of job specs skipped rather than published",
+		}),
+		specsFailed: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "market_sync_specs_failed_total",
+			Help: "Number of job specs that failed to be published",
+		}),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "market_sync_requests_total",
+			Help: "Number of requests to the Chainlink node and the Market, by response code (0 if no response)",
+		}, []string{"service", "method", "route", "code"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "market_sync_request_duration_seconds",
+			Help:    "Latency of requests to the Chainlink node and the Market",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"service", "method", "route"}),
+		lastReconcile: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "market_sync_last_reconcile_timestamp_seconds",
+			Help: "Unix time of the last successful reconcile of the node against the Market",
+		}),
+	}
+	m.registry.MustRegister(
+		m.specsSeen,
+		m.specsUnsynced,
+		m.specsSynced,
+		m.specsSkipped,
+		m.specsFailed,
+		m.requests,
+		m.requestDuration,
+		m.lastReconcile,
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+	)
+	return m
+}
+
+// Serve listens on the address in the background, serving /metrics, /healthz and /readyz
+func (m *Metrics) Serve(addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{}))
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("ok"))
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, _ *http.Request) {
+		if atomic.LoadInt32(&m.ready) == 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte("not ready"))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("ok"))
+	})
+
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	go func() {
+		if err := http.Serve(l, mux); err != nil {
+			displayError(err)
+		}
+	}()
+	client.ObserveRequests(m.ObserveRequest)
+	return nil
+}
+
+func (m *Metrics) ObserveRequest(service, method, route string, code int, duration time.Duration, _ error) {
+	if m == nil {
+		return
+	}
+	m.requests.WithLabelValues(service, method, route, strconv.Itoa(code)).Inc()
+	m.requestDuration.WithLabelValues(service, method, route).Observe(duration.Seconds())
+}
+
+func (m *Metrics) SpecsSeen(seen, unsynced int) {
+	if m == nil {
+		return
+	}
+	m.specsSeen.Set(float64(seen))
+	m.specsUnsynced.Set(float64(unsynced))
+}
+
+func (m *Metrics) SpecSynced() {
+	if m != nil {
+		m.specsSynced.Inc()
+	}
+}
+
+func (m *Metrics) SpecSkipped() {
+	if m != nil {
+		m.specsSkipped.Inc()
+	}
+}
+
+func (m *Metrics) SpecFailed() {
+	if m != nil {
+		m.specsFailed.Inc()
+	}
+}
+
+// Reconciled records a successful reconcile, marking the process as ready
+func (m *Metrics) Reconciled() {
+	if m == nil {
+		return
+	}
+	m.lastReconcile.SetToCurrentTime()
+	atomic.StoreInt32(&m.ready, 1)
+}
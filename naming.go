@@ -0,0 +1,208 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"market-sync/client"
+	"net/url"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+const (
+	jobNameMinLength = 2
+	jobNameMaxLength = 30
+)
+
+var (
+	jobNameMatcher     = regexp.MustCompile(`^[a-zA-Z0-9_\-\.\ \+\>\=]{2,30}$`)
+	jobNameInvalidChar = regexp.MustCompile(`[^a-zA-Z0-9_\-\.\ \+\>\=]+`)
+	jobNameRepeatedSep = regexp.MustCompile(`-{2,}`)
+)
+
+// JobNameData is what's available to job naming templates, eg:
+//
+//	{{.FirstHttpHost}}-{{.ResultType}}-{{.Network}}
+type JobNameData struct {
+	ID            string
+	ShortID       string
+	Initiator     string
+	FirstHttpHost string
+	HttpHosts     []string
+	JsonPath      string
+	Times         string
+	ResultType    string
+	Network       string
+}
+
+// JobNamer generates job names from a template, ensuring they're valid and
+// don't collide with any name already on the Market or given during this sync
+type JobNamer struct {
+	template *template.Template
+	taken    map[string]bool
+	// claimed are the names given to job specs during this sync, by spec ID
+	claimed map[string]string
+}
+
+func NewJobNamer(tmpl string) (*JobNamer, error) {
+	n := &JobNamer{taken: map[string]bool{}, claimed: map[string]string{}}
+	if len(tmpl) == 0 {
+		return n, nil
+	}
+	t, err := template.New("name").Funcs(template.FuncMap{
+		"lower": strings.ToLower,
+		"upper": strings.ToUpper,
+		"join":  strings.Join,
+	}).Parse(tmpl)
+	if err != nil {
+		return nil, fmt.Errorf("invalid job name template: %v", err)
+	}
+	n.template = t
+	return n, nil
+}
+
+func (n *JobNamer) HasTemplate() bool {
+	return n.template != nil
+}
+
+// Reserve marks names as being in use
+func (n *JobNamer) Reserve(names ...string) {
+	for _, name := range names {
+		n.taken[strings.ToLower(name)] = true
+	}
+}
+
+// Release frees names, so they can be used again
+func (n *JobNamer) Release(names ...string) {
+	for _, name := range names {
+		delete(n.taken, strings.ToLower(name))
+	}
+}
+
+// Claim reserves the name for the job spec, releasing any name it was given before
+func (n *JobNamer) Claim(specID, name string) {
+	n.Unclaim(specID)
+	n.Reserve(name)
+	n.claimed[specID] = name
+}
+
+// Unclaim releases the name given to the job spec, so it can be used again, returning
+// the name or an empty string if it had none
+func (n *JobNamer) Unclaim(specID string) string {
+	name, ok := n.claimed[specID]
+	if !ok {
+		return ""
+	}
+	n.Release(name)
+	delete(n.claimed, specID)
+	return name
+}
+
+func (n *JobNamer) Taken(name string) bool {
+	return n.taken[strings.ToLower(name)]
+}
+
+// Name renders the template for the spec, returning a valid name that isn't taken
+func (n *JobNamer) Name(spec *client.ChainlinkJobSpec, network string) (string, error) {
+	if n.template == nil {
+		return "", errors.New("no job name template set")
+	}
+	var b bytes.Buffer
+	if err := n.template.Execute(&b, NewJobNameData(spec, network)); err != nil {
+		return "", err
+	}
+	name := SanitizeJobName(b.String())
+	if len(name) < jobNameMinLength {
+		name = SanitizeJobName("job-" + spec.ID)
+	}
+	return n.unique(name), nil
+}
+
+// unique appends a counter to the name until it doesn't collide, truncating to fit
+func (n *JobNamer) unique(name string) string {
+	candidate := name
+	for i := 2; n.Taken(candidate); i++ {
+		suffix := fmt.Sprintf("-%d", i)
+		base := name
+		if len(base)+len(suffix) > jobNameMaxLength {
+			base = strings.TrimRight(base[:jobNameMaxLength-len(suffix)], " -._")
+		}
+		candidate = base + suffix
+	}
+	return candidate
+}
+
+// SanitizeJobName replaces any characters not allowed in a Market job name,
+// and truncates it to the maximum length
+func SanitizeJobName(name string) string {
+	name = jobNameInvalidChar.ReplaceAllString(strings.TrimSpace(name), "-")
+	name = jobNameRepeatedSep.ReplaceAllString(name, "-")
+	name = strings.Trim(name, " -")
+	if len(name) > jobNameMaxLength {
+		name = strings.TrimRight(name[:jobNameMaxLength], " -._")
+	}
+	return name
+}
+
+func NewJobNameData(spec *client.ChainlinkJobSpec, network string) *JobNameData {
+	d := &JobNameData{ID: spec.ID, ShortID: spec.ID, Network: network}
+	if len(d.ShortID) > 8 {
+		d.ShortID = d.ShortID[:8]
+	}
+	if len(spec.Attributes.Initiators) > 0 {
+		d.Initiator = spec.Attributes.Initiators[0].Type
+	}
+	for _, t := range spec.Attributes.Tasks {
+		switch strings.ToLower(t.Type) {
+		case "httpget", "httppost":
+			for _, key := range []string{"get", "post", "url"} {
+				if host := urlHost(t.Params[key]); len(host) > 0 {
+					d.HttpHosts = append(d.HttpHosts, host)
+					break
+				}
+			}
+		case "jsonparse":
+			d.JsonPath = jsonPath(t.Params["path"])
+		case "multiply":
+			if times, ok := t.Params["times"]; ok {
+				d.Times = fmt.Sprint(times)
+			}
+		case "ethuint256", "ethint256", "ethbytes32", "ethbool":
+			d.ResultType = strings.TrimPrefix(strings.ToLower(t.Type), "eth")
+		}
+	}
+	if len(d.HttpHosts) > 0 {
+		d.FirstHttpHost = d.HttpHosts[0]
+	}
+	return d
+}
+
+func urlHost(v interface{}) string {
+	s, ok := v.(string)
+	if !ok || len(s) == 0 {
+		return ""
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(u.Hostname(), "www.")
+}
+
+// jsonPath joins a jsonparse path, which is either a dot delimited string or an array
+func jsonPath(v interface{}) string {
+	switch p := v.(type) {
+	case string:
+		return p
+	case []interface{}:
+		var parts []string
+		for _, e := range p {
+			parts = append(parts, fmt.Sprint(e))
+		}
+		return strings.Join(parts, ".")
+	default:
+		return ""
+	}
+}
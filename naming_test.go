@@ -0,0 +1,127 @@
+package main
+
+import (
+	"market-sync/client"
+	"market-sync/prompt"
+	"testing"
+)
+
+func TestApplication_promptJobNameRename(t *testing.T) {
+	namer, err := NewJobNamer("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	namer.Reserve("market-job")
+	a := &Application{config: &Config{}, namer: namer}
+	spec := &client.ChainlinkJobSpec{ID: "a1b2c3d4"}
+
+	tests := []struct {
+		name    string
+		answers []string
+		want    string
+		wantErr bool
+	}{
+		{"first name", []string{"eth-usd"}, "eth-usd", false},
+		{"keeps its own name", []string{""}, "eth-usd", false},
+		{"renamed", []string{"eth-usd-2"}, "eth-usd-2", false},
+		{"name on the market", []string{"market-job"}, "eth-usd-2", true},
+		{"old name is free again", []string{"eth-usd"}, "eth-usd", false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			a.config.Prompter = prompt.NewScripted(test.answers, nil)
+			name, err := a.promptJobName(spec)
+			if test.wantErr != (err != nil) {
+				t.Fatalf("expected error %v, got %v", test.wantErr, err)
+			} else if err == nil {
+				spec.Name = name
+			}
+			if spec.Name != test.want {
+				t.Fatalf("expected name %s, got %s", test.want, spec.Name)
+			} else if !namer.Taken(spec.Name) {
+				t.Fatalf("expected %s to still be reserved", spec.Name)
+			}
+		})
+	}
+	if namer.Taken("eth-usd-2") {
+		t.Fatal("expected the replaced name to be released")
+	}
+}
+
+func TestSanitizeJobName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"eth-usd", "eth-usd"},
+		{"  eth usd  ", "eth usd"},
+		{"api.example.com/price?x=1", "api.example.com-price-x=1"},
+		{"eth//usd", "eth-usd"},
+		{"--eth-usd--", "eth-usd"},
+		{"a-very-long-job-name-that-goes-on-and-on", "a-very-long-job-name-that-goes"},
+		{"a-very-long-job-name-that-is-cut-here", "a-very-long-job-name-that-is-c"},
+		{"a-very-long-job-name-that-is-.-cut", "a-very-long-job-name-that-is"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := SanitizeJobName(test.name)
+			if got != test.want {
+				t.Errorf("expected %q, got %q", test.want, got)
+			} else if len(got) > jobNameMaxLength {
+				t.Errorf("expected at most %d characters, got %d", jobNameMaxLength, len(got))
+			}
+		})
+	}
+}
+
+func TestJobNamer_unique(t *testing.T) {
+	long := SanitizeJobName("a-very-long-job-name-that-goes-on-and-on")
+	tests := []struct {
+		name  string
+		taken []string
+		input string
+		want  string
+	}{
+		{"free", nil, "eth-usd", "eth-usd"},
+		{"taken", []string{"eth-usd"}, "eth-usd", "eth-usd-2"},
+		{"taken ignoring case", []string{"ETH-USD"}, "eth-usd", "eth-usd-2"},
+		{"suffixes taken", []string{"eth-usd", "eth-usd-2", "eth-usd-3"}, "eth-usd", "eth-usd-4"},
+		{"truncated to fit the suffix", []string{long}, long, "a-very-long-job-name-that-go-2"},
+		{"truncated without a trailing separator", []string{"a-very-long-job-name-that-is-x"}, "a-very-long-job-name-that-is-x", "a-very-long-job-name-that-is-2"},
+		{"truncated for a longer suffix", []string{long, "a-very-long-job-name-that-go-2"}, long, "a-very-long-job-name-that-go-3"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			n, err := NewJobNamer("")
+			if err != nil {
+				t.Fatal(err)
+			}
+			n.Reserve(test.taken...)
+			got := n.unique(test.input)
+			if got != test.want {
+				t.Errorf("expected %q, got %q", test.want, got)
+			} else if !jobNameMatcher.MatchString(got) {
+				t.Errorf("expected %q to be a valid job name", got)
+			}
+		})
+	}
+}
+
+func TestJobNamer_Name(t *testing.T) {
+	n, err := NewJobNamer("{{.FirstHttpHost}}-{{.Network}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec := &client.ChainlinkJobSpec{ID: "a1b2c3d4e5f6"}
+	spec.Attributes.Tasks = []*client.ChainlinkTaskSpec{
+		{Type: "httpget", Params: map[string]interface{}{"get": "https://min-api.cryptocompare-example.com/data/price"}},
+	}
+	n.Reserve("min-api.cryptocompare-example")
+	got, err := n.Name(spec, "mainnet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "min-api.cryptocompare-exampl-2"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"market-sync/syncer"
+)
+
+const NodeFileFlag = "node-file"
+
+func generateNodeCmd() *cobra.Command {
+	newcmd := &cobra.Command{
+		Use:   "node",
+		Short: "Manage the Chainlink node's listing on the Market",
+	}
+	register := &cobra.Command{
+		Use:   "register",
+		Short: "Create the Market node for the oracle address, with the details in the node file",
+		Args:  cobra.MaximumNArgs(0),
+		Run:   runNodeRegister,
+	}
+	update := &cobra.Command{
+		Use:   "update",
+		Short: "Update the Market node's details from the node file",
+		Args:  cobra.MaximumNArgs(0),
+		Run:   runNodeUpdate,
+	}
+	for _, cmd := range []*cobra.Command{register, update} {
+		cmd.Flags().String(NodeFileFlag, "market-node.json", "file (json) with the node's name, description and website")
+		newcmd.AddCommand(cmd)
+	}
+	return newcmd
+}
+
+func runNodeRegister(_ *cobra.Command, _ []string) {
+	yellow := color.New(color.FgYellow).SprintFunc()
+	a, metadata := newNodeApplication()
+	created, err := a.syncer.RegisterNode(a.market, metadata)
+	if err != nil {
+		exit(err)
+	}
+	a.printNodeDetails()
+	fmt.Printf("%s %s\n", yellow("Market Node ID:"), created.ID.String())
+	color.Green("Node %s registered on the Market", metadata.Name)
+	exit(nil)
+}
+
+func runNodeUpdate(_ *cobra.Command, _ []string) {
+	yellow := color.New(color.FgYellow).SprintFunc()
+	a, metadata := newNodeApplication()
+	node, err := a.syncer.UpdateNode(a.market, metadata)
+	if err != nil {
+		exit(err)
+	}
+	a.printNodeDetails()
+	fmt.Printf("%s %s\n", yellow("Market Node ID:"), node.ID.String())
+	color.Green("Node %s updated on the Market", metadata.Name)
+	exit(nil)
+}
+
+func newNodeApplication() (*Application, *syncer.NodeMetadata) {
+	requireFlags(append(nodeFlags, marketFlags...)...)
+	metadata, err := syncer.LoadNodeMetadata(viper.GetString(NodeFileFlag))
+	if err != nil {
+		exit(err)
+	}
+	a, err := NewApplication(newConfig(&Config{}))
+	if err != nil {
+		exit(err)
+	}
+	return a, metadata
+}
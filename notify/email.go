@@ -0,0 +1,36 @@
+package notify
+
+import (
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+)
+
+// EmailSink sends the event as an email through the SMTP server
+type EmailSink struct {
+	Addr     string
+	Username string
+	Password string
+	From     string
+	To       []string
+}
+
+func (s *EmailSink) Notify(e *Event) error {
+	var auth smtp.Auth
+	if len(s.Username) > 0 {
+		host, _, err := net.SplitHostPort(s.Addr)
+		if err != nil {
+			return err
+		}
+		auth = smtp.PlainAuth("", s.Username, s.Password, host)
+	}
+	msg := fmt.Sprintf(
+		"From: %s\r\nTo: %s\r\nSubject: market-sync: %s\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%s\r\n",
+		s.From,
+		strings.Join(s.To, ", "),
+		e.Title(),
+		strings.Replace(e.Text(), "\n", "\r\n", -1),
+	)
+	return smtp.SendMail(s.Addr, auth, s.From, s.To, []byte(msg))
+}
@@ -0,0 +1,81 @@
+package notify
+
+import (
+	"fmt"
+	"go.uber.org/multierr"
+	"strings"
+	"time"
+)
+
+type EventType string
+
+const (
+	EventUnsyncedJobs   EventType = "unsynced_jobs"
+	EventJobCreated     EventType = "job_created"
+	EventJobFailed      EventType = "job_failed"
+	EventSecretDetected EventType = "secret_detected"
+)
+
+// Event is sent to every sink, and is the JSON payload of the generic webhook
+type Event struct {
+	Type        EventType `json:"type"`
+	Time        time.Time `json:"time"`
+	NodeJobIDs  []string  `json:"nodeJobIds,omitempty"`
+	JobName     string    `json:"jobName,omitempty"`
+	MarketJobID string    `json:"marketJobId,omitempty"`
+	Details     []string  `json:"details,omitempty"`
+	Error       string    `json:"error,omitempty"`
+}
+
+func NewEvent(t EventType, nodeJobIDs ...string) *Event {
+	return &Event{Type: t, Time: time.Now().UTC(), NodeJobIDs: nodeJobIDs}
+}
+
+// Title is a one line summary of the event
+func (e *Event) Title() string {
+	switch e.Type {
+	case EventUnsyncedJobs:
+		return fmt.Sprintf("%d new job specs on the node aren't on the Market", len(e.NodeJobIDs))
+	case EventJobCreated:
+		return fmt.Sprintf("Job %s was published to the Market", e.JobName)
+	case EventJobFailed:
+		return fmt.Sprintf("Job %s failed to be published to the Market", e.JobName)
+	case EventSecretDetected:
+		return fmt.Sprintf("Possible secrets detected in job spec %s", strings.Join(e.NodeJobIDs, ", "))
+	default:
+		return string(e.Type)
+	}
+}
+
+// Text is the full description of the event, used by the chat and email sinks
+func (e *Event) Text() string {
+	lines := []string{e.Title()}
+	if len(e.NodeJobIDs) > 0 {
+		lines = append(lines, "Node job IDs: "+strings.Join(e.NodeJobIDs, ", "))
+	}
+	if len(e.MarketJobID) > 0 {
+		lines = append(lines, "Market job ID: "+e.MarketJobID)
+	}
+	for _, d := range e.Details {
+		lines = append(lines, "- "+d)
+	}
+	if len(e.Error) > 0 {
+		lines = append(lines, "Error: "+e.Error)
+	}
+	return strings.Join(lines, "\n")
+}
+
+type Sink interface {
+	Notify(e *Event) error
+}
+
+// Notifier sends every event to all of its sinks
+type Notifier []Sink
+
+func (n Notifier) Notify(e *Event) error {
+	var merr error
+	for _, s := range n {
+		merr = multierr.Append(merr, s.Notify(e))
+	}
+	return merr
+}
@@ -0,0 +1,53 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// WebhookSink posts the event as JSON to the URL
+type WebhookSink struct {
+	URL    string
+	Client *http.Client
+}
+
+func NewWebhookSink(url string) *WebhookSink {
+	return &WebhookSink{URL: url, Client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (w *WebhookSink) Notify(e *Event) error {
+	return postJSON(w.Client, w.URL, e)
+}
+
+// SlackSink posts the event to a Slack compatible incoming webhook
+type SlackSink struct {
+	URL    string
+	Client *http.Client
+}
+
+func NewSlackSink(url string) *SlackSink {
+	return &SlackSink{URL: url, Client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (s *SlackSink) Notify(e *Event) error {
+	return postJSON(s.Client, s.URL, map[string]string{"text": e.Text()})
+}
+
+func postJSON(client *http.Client, url string, body interface{}) error {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Post(url, "application/json", bytes.NewBuffer(b))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("notify: unexpected response code %d from %s", resp.StatusCode, url)
+	}
+	return nil
+}
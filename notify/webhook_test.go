@@ -0,0 +1,133 @@
+package notify
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// recorder is a local HTTP server that records the body of every request posted to it
+type recorder struct {
+	*httptest.Server
+	bodies [][]byte
+	status int
+}
+
+func newRecorder(t *testing.T, status int) *recorder {
+	r := &recorder{status: status}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			t.Errorf("expected a POST, got %s", req.Method)
+		}
+		if ct := req.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("expected an application/json body, got %q", ct)
+		}
+		b, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r.bodies = append(r.bodies, b)
+		w.WriteHeader(r.status)
+	}))
+	return r
+}
+
+func TestWebhookSink_Notify(t *testing.T) {
+	r := newRecorder(t, http.StatusOK)
+	defer r.Close()
+
+	e := NewEvent(EventJobCreated, "a1b2c3")
+	e.JobName, e.MarketJobID = "eth-usd", "6b0e3f1e-3a7e-4c1b-9c55-2b8f3f2b7b11"
+	if err := NewWebhookSink(r.URL).Notify(e); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(r.bodies) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(r.bodies))
+	}
+
+	var got Event
+	if err := json.Unmarshal(r.bodies[0], &got); err != nil {
+		t.Fatalf("webhook body isn't an event: %v", err)
+	}
+	if got.Type != EventJobCreated {
+		t.Errorf("expected type %s, got %s", EventJobCreated, got.Type)
+	}
+	if got.JobName != e.JobName || got.MarketJobID != e.MarketJobID {
+		t.Errorf("expected job %s (%s), got %s (%s)", e.JobName, e.MarketJobID, got.JobName, got.MarketJobID)
+	}
+	if len(got.NodeJobIDs) != 1 || got.NodeJobIDs[0] != "a1b2c3" {
+		t.Errorf("expected node job IDs [a1b2c3], got %v", got.NodeJobIDs)
+	}
+	if !got.Time.Equal(e.Time) {
+		t.Errorf("expected time %s, got %s", e.Time, got.Time)
+	}
+}
+
+func TestSlackSink_Notify(t *testing.T) {
+	r := newRecorder(t, http.StatusOK)
+	defer r.Close()
+
+	e := NewEvent(EventJobFailed, "a1b2c3")
+	e.JobName, e.Error = "eth-usd", "market: validation failed"
+	if err := NewSlackSink(r.URL).Notify(e); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(r.bodies) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(r.bodies))
+	}
+
+	var got map[string]string
+	if err := json.Unmarshal(r.bodies[0], &got); err != nil {
+		t.Fatalf("slack body isn't a message: %v", err)
+	}
+	if len(got) != 1 {
+		t.Errorf("expected only a text field, got %v", got)
+	}
+	if got["text"] != e.Text() {
+		t.Errorf("expected text %q, got %q", e.Text(), got["text"])
+	}
+	for _, want := range []string{"Job eth-usd failed to be published", "Node job IDs: a1b2c3", "Error: market: validation failed"} {
+		if !strings.Contains(got["text"], want) {
+			t.Errorf("expected text to contain %q, got %q", want, got["text"])
+		}
+	}
+}
+
+func TestSinks_ErrorStatus(t *testing.T) {
+	r := newRecorder(t, http.StatusInternalServerError)
+	defer r.Close()
+
+	for name, sink := range map[string]Sink{
+		"webhook": NewWebhookSink(r.URL),
+		"slack":   NewSlackSink(r.URL),
+	} {
+		err := sink.Notify(NewEvent(EventUnsyncedJobs, "a1b2c3"))
+		if err == nil || !strings.Contains(err.Error(), "500") {
+			t.Errorf("%s: expected an error with the response code, got %v", name, err)
+		}
+	}
+}
+
+type failingSink struct{}
+
+func (failingSink) Notify(*Event) error {
+	return errors.New("sink failed")
+}
+
+func TestNotifier_NotifiesEverySink(t *testing.T) {
+	r := newRecorder(t, http.StatusOK)
+	defer r.Close()
+
+	n := Notifier{failingSink{}, NewWebhookSink(r.URL), NewSlackSink(r.URL)}
+	err := n.Notify(NewEvent(EventUnsyncedJobs, "a1b2c3", "d4e5f6"))
+	if err == nil || !strings.Contains(err.Error(), "sink failed") {
+		t.Errorf("expected the failing sink's error, got %v", err)
+	}
+	if len(r.bodies) != 2 {
+		t.Errorf("expected the sinks after the failing one to be notified, got %d requests", len(r.bodies))
+	}
+}
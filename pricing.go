@@ -0,0 +1,202 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"market-sync/client"
+	"math/big"
+	"strings"
+)
+
+const (
+	PricingStrategyFlat       = "flat"
+	PricingStrategyTask       = "task"
+	PricingStrategyInitiator  = "initiator"
+	PricingStrategyMultiplier = "multiplier"
+)
+
+// Pricer calculates the cost of a job spec so it doesn't have to be entered manually.
+// The minimum is the node's MINIMUM_CONTRACT_PAYMENT, which can be nil if not known.
+type Pricer interface {
+	Strategy() string
+	Price(spec *client.ChainlinkJobSpec, minimum *client.Link) (*client.Link, error)
+}
+
+// PricingRules is the format of the pricing rules file, eg:
+//
+//	{
+//	  "strategy": "task",
+//	  "default": "0.05 LINK",
+//	  "tasks": {"httpget": "0.05 LINK", "ethtx": "0.02 LINK"}
+//	}
+type PricingRules struct {
+	Strategy   string                  `json:"strategy"`
+	Flat       *client.Link            `json:"flat"`
+	Default    *client.Link            `json:"default"`
+	Tasks      map[string]*client.Link `json:"tasks"`
+	Initiators map[string]*client.Link `json:"initiators"`
+	Multiplier string                  `json:"multiplier"`
+}
+
+func LoadPricingRules(path string) (*PricingRules, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	rules := &PricingRules{}
+	if err := json.Unmarshal(b, rules); err != nil {
+		return nil, fmt.Errorf("invalid pricing rules file %s: %v", path, err)
+	}
+	return rules, nil
+}
+
+// NewPricer returns the pricer for the strategy set in the rules, or nil if no strategy is set
+func NewPricer(rules *PricingRules) (Pricer, error) {
+	switch strings.ToLower(rules.Strategy) {
+	case "":
+		return nil, nil
+	case PricingStrategyFlat:
+		if rules.Flat == nil {
+			return nil, errors.New("flat pricing requires a flat price")
+		}
+		return &flatPricer{price: rules.Flat}, nil
+	case PricingStrategyTask:
+		if len(rules.Tasks) == 0 && rules.Default == nil {
+			return nil, errors.New("task pricing requires task prices or a default price")
+		}
+		return &taskPricer{prices: lowerKeys(rules.Tasks), fallback: rules.Default}, nil
+	case PricingStrategyInitiator:
+		if len(rules.Initiators) == 0 && rules.Default == nil {
+			return nil, errors.New("initiator pricing requires initiator prices or a default price")
+		}
+		return &initiatorPricer{prices: lowerKeys(rules.Initiators), fallback: rules.Default}, nil
+	case PricingStrategyMultiplier:
+		m, ok := new(big.Rat).SetString(rules.Multiplier)
+		if !ok || m.Sign() <= 0 {
+			return nil, fmt.Errorf("invalid pricing multiplier %q, must be a positive number", rules.Multiplier)
+		}
+		return &multiplierPricer{multiplier: m}, nil
+	default:
+		return nil, fmt.Errorf(
+			"unknown pricing strategy %q, must be one of: %s",
+			rules.Strategy,
+			strings.Join([]string{
+				PricingStrategyFlat,
+				PricingStrategyTask,
+				PricingStrategyInitiator,
+				PricingStrategyMultiplier,
+			}, ", "),
+		)
+	}
+}
+
+type flatPricer struct {
+	price *client.Link
+}
+
+func (p *flatPricer) Strategy() string {
+	return PricingStrategyFlat
+}
+
+func (p *flatPricer) Price(_ *client.ChainlinkJobSpec, _ *client.Link) (*client.Link, error) {
+	return p.price, nil
+}
+
+// taskPricer sums the price of every task in the job, keyed by task type or bridge name
+type taskPricer struct {
+	prices   map[string]*client.Link
+	fallback *client.Link
+}
+
+func (p *taskPricer) Strategy() string {
+	return PricingStrategyTask
+}
+
+func (p *taskPricer) Price(spec *client.ChainlinkJobSpec, _ *client.Link) (*client.Link, error) {
+	total := new(big.Int)
+	for _, t := range spec.Attributes.Tasks {
+		price, ok := p.prices[strings.ToLower(t.Type)]
+		if !ok {
+			price = p.fallback
+		}
+		if price == nil {
+			return nil, fmt.Errorf("no price for task type %s", t.Type)
+		}
+		total.Add(total, price.ToInt())
+	}
+	return (*client.Link)(total), nil
+}
+
+// initiatorPricer prices the job by the first initiator with a price set
+type initiatorPricer struct {
+	prices   map[string]*client.Link
+	fallback *client.Link
+}
+
+func (p *initiatorPricer) Strategy() string {
+	return PricingStrategyInitiator
+}
+
+func (p *initiatorPricer) Price(spec *client.ChainlinkJobSpec, _ *client.Link) (*client.Link, error) {
+	var types []string
+	for _, i := range spec.Attributes.Initiators {
+		if price, ok := p.prices[strings.ToLower(i.Type)]; ok {
+			return price, nil
+		}
+		types = append(types, i.Type)
+	}
+	if p.fallback == nil {
+		return nil, fmt.Errorf("no price for initiator types: %s", strings.Join(types, ", "))
+	}
+	return p.fallback, nil
+}
+
+// multiplierPricer multiplies the spec's MinPayment, falling back to the node's minimum payment
+type multiplierPricer struct {
+	multiplier *big.Rat
+}
+
+func (p *multiplierPricer) Strategy() string {
+	return PricingStrategyMultiplier
+}
+
+func (p *multiplierPricer) Price(spec *client.ChainlinkJobSpec, minimum *client.Link) (*client.Link, error) {
+	base := minimum
+	if len(spec.MinPayment) > 0 {
+		var err error
+		if base, err = client.ParseLink(spec.MinPayment); err != nil {
+			return nil, err
+		}
+	}
+	if base == nil {
+		return nil, errors.New("job spec has no minimum payment and the node's minimum payment is unknown")
+	}
+	r := new(big.Rat).Mul(new(big.Rat).SetInt(base.ToInt()), p.multiplier)
+	return (*client.Link)(new(big.Int).Quo(r.Num(), r.Denom())), nil
+}
+
+func lowerKeys(m map[string]*client.Link) map[string]*client.Link {
+	l := map[string]*client.Link{}
+	for k, v := range m {
+		l[strings.ToLower(k)] = v
+	}
+	return l
+}
+
+func taskTypes(spec *client.ChainlinkJobSpec) []string {
+	var types []string
+	for _, t := range spec.Attributes.Tasks {
+		types = append(types, t.Type)
+	}
+	return types
+}
+
+func initiatorTypes(spec *client.ChainlinkJobSpec) []string {
+	var types []string
+	for _, i := range spec.Attributes.Initiators {
+		types = append(types, i.Type)
+	}
+	return types
+}
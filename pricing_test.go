@@ -0,0 +1,115 @@
+package main
+
+import (
+	"market-sync/client"
+	"testing"
+)
+
+// pricingSpec returns a job spec with the task and initiator types
+func pricingSpec(minPayment string, initiators []string, tasks ...string) *client.ChainlinkJobSpec {
+	spec := &client.ChainlinkJobSpec{ID: "a1b2c3d4", MinPayment: minPayment}
+	for _, i := range initiators {
+		spec.Attributes.Initiators = append(spec.Attributes.Initiators, &client.ChainlinkInitiator{Type: i})
+	}
+	for _, t := range tasks {
+		spec.Attributes.Tasks = append(spec.Attributes.Tasks, &client.ChainlinkTaskSpec{Type: t})
+	}
+	return spec
+}
+
+func TestPricer_Price(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   *PricingRules
+		spec    *client.ChainlinkJobSpec
+		minimum *client.Link
+		want    string
+		wantErr bool
+	}{
+		{"flat", &PricingRules{Strategy: "flat", Flat: client.NewLink(5)}, pricingSpec("", nil, "httpget"), nil, "5", false},
+		{
+			"task sum",
+			&PricingRules{Strategy: "task", Tasks: map[string]*client.Link{"HttpGet": client.NewLink(3), "ethtx": client.NewLink(2)}},
+			pricingSpec("", nil, "httpget", "ethtx", "httpget"), nil, "8", false,
+		},
+		{
+			"task default",
+			&PricingRules{Strategy: "task", Tasks: map[string]*client.Link{"httpget": client.NewLink(3)}, Default: client.NewLink(1)},
+			pricingSpec("", nil, "httpget", "jsonparse"), nil, "4", false,
+		},
+		{
+			"task without price",
+			&PricingRules{Strategy: "task", Tasks: map[string]*client.Link{"httpget": client.NewLink(3)}},
+			pricingSpec("", nil, "httpget", "jsonparse"), nil, "", true,
+		},
+		{
+			"no tasks",
+			&PricingRules{Strategy: "task", Default: client.NewLink(1)},
+			pricingSpec("", nil), nil, "0", false,
+		},
+		{
+			"first priced initiator",
+			&PricingRules{Strategy: "initiator", Initiators: map[string]*client.Link{"runlog": client.NewLink(7), "cron": client.NewLink(9)}},
+			pricingSpec("", []string{"web", "RunLog", "cron"}), nil, "7", false,
+		},
+		{
+			"initiator default",
+			&PricingRules{Strategy: "initiator", Initiators: map[string]*client.Link{"cron": client.NewLink(9)}, Default: client.NewLink(2)},
+			pricingSpec("", []string{"runlog"}), nil, "2", false,
+		},
+		{
+			"initiator without price",
+			&PricingRules{Strategy: "initiator", Initiators: map[string]*client.Link{"cron": client.NewLink(9)}},
+			pricingSpec("", []string{"runlog"}), nil, "", true,
+		},
+		{"multiplier", &PricingRules{Strategy: "multiplier", Multiplier: "1.5"}, pricingSpec("10", nil), nil, "15", false},
+		{"multiplier truncates", &PricingRules{Strategy: "multiplier", Multiplier: "1.5"}, pricingSpec("5", nil), nil, "7", false},
+		{"multiplier fraction truncates", &PricingRules{Strategy: "multiplier", Multiplier: "1/3"}, pricingSpec("0.1 LINK", nil), nil, "33333333333333333", false},
+		{"multiplier node minimum", &PricingRules{Strategy: "multiplier", Multiplier: "2"}, pricingSpec("", nil), client.NewLink(4), "8", false},
+		{"multiplier no minimum", &PricingRules{Strategy: "multiplier", Multiplier: "2"}, pricingSpec("", nil), nil, "", true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p, err := NewPricer(test.rules)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := p.Price(test.spec, test.minimum)
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %s", got)
+				}
+				return
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if got.String() != test.want {
+				t.Errorf("expected %s juels, got %s", test.want, got)
+			}
+		})
+	}
+}
+
+func TestNewPricer(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   *PricingRules
+		wantErr bool
+	}{
+		{"no strategy", &PricingRules{}, false},
+		{"flat without price", &PricingRules{Strategy: "flat"}, true},
+		{"task without prices", &PricingRules{Strategy: "task"}, true},
+		{"initiator without prices", &PricingRules{Strategy: "initiator"}, true},
+		{"zero multiplier", &PricingRules{Strategy: "multiplier", Multiplier: "0"}, true},
+		{"negative multiplier", &PricingRules{Strategy: "multiplier", Multiplier: "-1"}, true},
+		{"invalid multiplier", &PricingRules{Strategy: "multiplier", Multiplier: "double"}, true},
+		{"unknown strategy", &PricingRules{Strategy: "auction"}, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := NewPricer(test.rules); test.wantErr != (err != nil) {
+				t.Errorf("expected error %v, got %v", test.wantErr, err)
+			}
+		})
+	}
+}
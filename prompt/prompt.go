@@ -0,0 +1,67 @@
+package prompt
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// ErrNoAnswer is returned when a non-interactive prompter has no answer for a question
+var ErrNoAnswer = errors.New("prompt: no answer for question")
+
+// Prompter asks the operator questions, so the sync can be driven by a terminal,
+// a script of answers, rules or any other frontend
+type Prompter interface {
+	// Confirm asks a yes/no question
+	Confirm(question string, def bool) (bool, error)
+	// Ask asks for a string, which must pass the options' validation
+	Ask(question string, opts *Options) (string, error)
+	// Select asks for one of the options
+	Select(question string, options []string) (string, error)
+}
+
+type Options struct {
+	Default  string
+	Required bool
+	Validate func(string) error
+}
+
+func (o *Options) validate(answer string) error {
+	if o == nil {
+		return nil
+	} else if o.Required && len(answer) == 0 {
+		return errors.New("an answer is required")
+	} else if o.Validate != nil {
+		return o.Validate(answer)
+	}
+	return nil
+}
+
+func (o *Options) defaultAnswer() string {
+	if o == nil {
+		return ""
+	}
+	return o.Default
+}
+
+func parseBool(answer string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes", "true":
+		return true, nil
+	case "n", "no", "false":
+		return false, nil
+	default:
+		return false, errors.New("answer needs to be y/n")
+	}
+}
+
+// selectOption matches the answer against an option, either by its text or 1 based index
+func selectOption(answer string, options []string) (string, error) {
+	answer = strings.TrimSpace(answer)
+	for i, o := range options {
+		if answer == o || answer == strconv.Itoa(i+1) {
+			return o, nil
+		}
+	}
+	return "", errors.New("answer isn't one of the options: " + strings.Join(options, ", "))
+}
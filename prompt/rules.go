@@ -0,0 +1,91 @@
+package prompt
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+)
+
+// Rule answers any question matching its regular expression
+type Rule struct {
+	Question string `json:"question"`
+	Answer   string `json:"answer"`
+
+	matcher *regexp.Regexp
+}
+
+// Rules answers questions from the first rule matching the question, falling back
+// to the question's default. Questions with no matching rule or default aren't answered.
+type Rules struct {
+	rules []*Rule
+	out   io.Writer
+}
+
+func NewRules(rules []*Rule, out io.Writer) (*Rules, error) {
+	for _, r := range rules {
+		m, err := regexp.Compile(r.Question)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rule question %q: %v", r.Question, err)
+		}
+		r.matcher = m
+	}
+	return &Rules{rules: rules, out: out}, nil
+}
+
+// LoadRules reads the rules from a JSON file, eg:
+//
+//	[{"question": "^Sync this job spec", "answer": "y"}]
+func LoadRules(path string, out io.Writer) (*Rules, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var rules []*Rule
+	if err := json.Unmarshal(b, &rules); err != nil {
+		return nil, fmt.Errorf("invalid rules file %s: %v", path, err)
+	}
+	return NewRules(rules, out)
+}
+
+func (r *Rules) answer(question string) (string, bool) {
+	for _, rule := range r.rules {
+		if rule.matcher.MatchString(question) {
+			if r.out != nil {
+				_, _ = fmt.Fprintf(r.out, "%s %s\n", question, rule.Answer)
+			}
+			return rule.Answer, true
+		}
+	}
+	return "", false
+}
+
+func (r *Rules) Confirm(question string, def bool) (bool, error) {
+	answer, ok := r.answer(question)
+	if !ok {
+		return def, nil
+	}
+	return parseBool(answer)
+}
+
+func (r *Rules) Ask(question string, opts *Options) (string, error) {
+	answer, ok := r.answer(question)
+	if !ok {
+		answer = opts.defaultAnswer()
+	}
+	if !ok && len(answer) == 0 {
+		return "", fmt.Errorf("%w: %s", ErrNoAnswer, question)
+	} else if err := opts.validate(answer); err != nil {
+		return "", fmt.Errorf("invalid rule answer for %s: %v", question, err)
+	}
+	return answer, nil
+}
+
+func (r *Rules) Select(question string, options []string) (string, error) {
+	answer, ok := r.answer(question)
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrNoAnswer, question)
+	}
+	return selectOption(answer, options)
+}
@@ -0,0 +1,126 @@
+package prompt
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRules_Matching(t *testing.T) {
+	r, err := NewRules([]*Rule{
+		{Question: "^Sync this job spec", Answer: "y"},
+		{Question: "(?i)edit", Answer: "n"},
+		{Question: "^Job name$", Answer: "eth-usd"},
+		{Question: "^Job", Answer: "0.1 LINK"},
+		{Question: "^Approve", Answer: "Reject"},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	confirms := []struct {
+		question string
+		def      bool
+		want     bool
+	}{
+		{"Sync this job spec to the Market?", false, true},
+		{"Edit job spec parameters?", true, false},
+		{"Retry adding this job?", true, true},
+		{"Retry adding this job?", false, false},
+	}
+	for _, test := range confirms {
+		got, err := r.Confirm(test.question, test.def)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.question, err)
+		} else if got != test.want {
+			t.Errorf("%s: expected %v, got %v", test.question, test.want, got)
+		}
+	}
+
+	asks := []struct {
+		question string
+		opts     *Options
+		want     string
+		wantErr  error
+	}{
+		// the first matching rule answers, even when a later rule also matches
+		{"Job name", nil, "eth-usd", nil},
+		{"Job cost (LINK or juels)", nil, "0.1 LINK", nil},
+		{"Market node name", &Options{Default: "LinkPool"}, "LinkPool", nil},
+		{"Market node name", &Options{Required: true}, "", ErrNoAnswer},
+		{"Job name", &Options{Validate: func(string) error { return errors.New("name taken") }}, "", errors.New("name taken")},
+	}
+	for _, test := range asks {
+		got, err := r.Ask(test.question, test.opts)
+		checkErr(t, err, test.wantErr)
+		if got != test.want {
+			t.Errorf("%s: expected %q, got %q", test.question, test.want, got)
+		}
+	}
+
+	if got, err := r.Select("Approve this publish request?", []string{"Approve", "Reject", "Skip"}); err != nil || got != "Reject" {
+		t.Errorf("expected Reject, got %q, %v", got, err)
+	}
+	if _, err := r.Select("Pick a network", []string{"mainnet", "kovan"}); !errors.Is(err, ErrNoAnswer) {
+		t.Errorf("expected no answer without a matching rule, got %v", err)
+	}
+}
+
+func TestRules_InvalidAnswer(t *testing.T) {
+	r, err := NewRules([]*Rule{{Question: ".*", Answer: "maybe"}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Confirm("Sync this job spec to the Market?", true); err == nil {
+		t.Error("expected an error for an answer that isn't y/n")
+	}
+	if _, err := r.Select("Approve this publish request?", []string{"Approve", "Reject"}); err == nil {
+		t.Error("expected an error for an answer that isn't an option")
+	}
+}
+
+func TestNewRules_InvalidQuestion(t *testing.T) {
+	if _, err := NewRules([]*Rule{{Question: "(", Answer: "y"}}, nil); err == nil {
+		t.Error("expected an error for an invalid regular expression")
+	}
+}
+
+func TestLoadRules(t *testing.T) {
+	dir, err := ioutil.TempDir("", "prompt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{"valid", `[{"question": "^Sync", "answer": "y"}]`, false},
+		{"invalid json", `{"question": "^Sync"`, true},
+		{"invalid question", `[{"question": "(", "answer": "y"}]`, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(dir, test.name+".json")
+			if err := ioutil.WriteFile(path, []byte(test.content), 0600); err != nil {
+				t.Fatal(err)
+			}
+			r, err := LoadRules(path, nil)
+			if test.wantErr {
+				if err == nil {
+					t.Error("expected an error")
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ok, err := r.Confirm("Sync this job spec to the Market?", false); err != nil || !ok {
+				t.Errorf("expected the loaded rule to confirm, got %v, %v", ok, err)
+			}
+		})
+	}
+}
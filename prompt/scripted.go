@@ -0,0 +1,81 @@
+package prompt
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// Scripted answers each question with the next line of a script, in order.
+// An empty line accepts the question's default.
+type Scripted struct {
+	mu      sync.Mutex
+	answers []string
+	out     io.Writer
+}
+
+func NewScripted(answers []string, out io.Writer) *Scripted {
+	return &Scripted{answers: answers, out: out}
+}
+
+// LoadScripted reads the answers from a file, one answer per line
+func LoadScripted(path string, out io.Writer) (*Scripted, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var answers []string
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		answers = append(answers, s.Text())
+	}
+	return NewScripted(answers, out), s.Err()
+}
+
+func (s *Scripted) next(question string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.answers) == 0 {
+		return "", fmt.Errorf("%w: %s", ErrNoAnswer, question)
+	}
+	answer := s.answers[0]
+	s.answers = s.answers[1:]
+	if s.out != nil {
+		_, _ = fmt.Fprintf(s.out, "%s %s\n", question, answer)
+	}
+	return answer, nil
+}
+
+func (s *Scripted) Confirm(question string, def bool) (bool, error) {
+	answer, err := s.next(question)
+	if err != nil {
+		return false, err
+	} else if len(answer) == 0 {
+		return def, nil
+	}
+	return parseBool(answer)
+}
+
+func (s *Scripted) Ask(question string, opts *Options) (string, error) {
+	answer, err := s.next(question)
+	if err != nil {
+		return "", err
+	} else if len(answer) == 0 {
+		answer = opts.defaultAnswer()
+	}
+	if err := opts.validate(answer); err != nil {
+		return "", fmt.Errorf("invalid scripted answer for %s: %v", question, err)
+	}
+	return answer, nil
+}
+
+func (s *Scripted) Select(question string, options []string) (string, error) {
+	answer, err := s.next(question)
+	if err != nil {
+		return "", err
+	}
+	return selectOption(answer, options)
+}
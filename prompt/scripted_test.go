@@ -0,0 +1,143 @@
+package prompt
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestScripted_Confirm(t *testing.T) {
+	tests := []struct {
+		name    string
+		answers []string
+		def     bool
+		want    bool
+		wantErr error
+	}{
+		{"yes", []string{"y"}, false, true, nil},
+		{"no", []string{"no"}, true, false, nil},
+		{"empty accepts default", []string{""}, true, true, nil},
+		{"exhausted", nil, true, false, ErrNoAnswer},
+		{"invalid", []string{"maybe"}, false, false, errors.New("answer needs to be y/n")},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := NewScripted(test.answers, nil).Confirm("Sync this job spec to the Market?", test.def)
+			checkErr(t, err, test.wantErr)
+			if got != test.want {
+				t.Errorf("expected %v, got %v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestScripted_Ask(t *testing.T) {
+	validate := func(s string) error {
+		if strings.Contains(s, " ") {
+			return errors.New("no spaces allowed")
+		}
+		return nil
+	}
+	tests := []struct {
+		name    string
+		answers []string
+		opts    *Options
+		want    string
+		wantErr error
+	}{
+		{"answer", []string{"eth-usd"}, &Options{Required: true}, "eth-usd", nil},
+		{"nil options", []string{"eth-usd"}, nil, "eth-usd", nil},
+		{"empty accepts default", []string{""}, &Options{Default: "0.1 LINK"}, "0.1 LINK", nil},
+		{"exhausted", nil, &Options{Default: "0.1 LINK"}, "", ErrNoAnswer},
+		{"required", []string{""}, &Options{Required: true}, "", errors.New("an answer is required")},
+		{"fails validation", []string{"eth usd"}, &Options{Validate: validate}, "", errors.New("no spaces allowed")},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := NewScripted(test.answers, nil).Ask("Job name", test.opts)
+			checkErr(t, err, test.wantErr)
+			if got != test.want {
+				t.Errorf("expected %q, got %q", test.want, got)
+			}
+		})
+	}
+}
+
+func TestScripted_Select(t *testing.T) {
+	options := []string{"Approve", "Reject", "Skip"}
+	tests := []struct {
+		name    string
+		answers []string
+		want    string
+		wantErr error
+	}{
+		{"by text", []string{"Reject"}, "Reject", nil},
+		{"by index", []string{"3"}, "Skip", nil},
+		{"exhausted", nil, "", ErrNoAnswer},
+		{"not an option", []string{"4"}, "", errors.New("answer isn't one of the options")},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := NewScripted(test.answers, nil).Select("Approve this publish request?", options)
+			checkErr(t, err, test.wantErr)
+			if got != test.want {
+				t.Errorf("expected %q, got %q", test.want, got)
+			}
+		})
+	}
+}
+
+func TestScripted_AnswersInOrder(t *testing.T) {
+	var out bytes.Buffer
+	s := NewScripted([]string{"y", "eth-usd"}, &out)
+	if ok, err := s.Confirm("Sync?", false); err != nil || !ok {
+		t.Fatalf("expected the first answer to confirm, got %v, %v", ok, err)
+	}
+	if name, err := s.Ask("Job name", nil); err != nil || name != "eth-usd" {
+		t.Fatalf("expected the second answer, got %q, %v", name, err)
+	}
+	if _, err := s.Ask("Job cost", nil); !errors.Is(err, ErrNoAnswer) {
+		t.Errorf("expected the answers to be exhausted, got %v", err)
+	}
+	if want := "Sync? y\nJob name eth-usd\n"; out.String() != want {
+		t.Errorf("expected the answers to be echoed as %q, got %q", want, out.String())
+	}
+}
+
+func TestLoadScripted(t *testing.T) {
+	dir, err := ioutil.TempDir("", "prompt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "answers.txt")
+	if err := ioutil.WriteFile(path, []byte("y\n\neth-usd\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := LoadScripted(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"y", "", "eth-usd"}; strings.Join(s.answers, ",") != strings.Join(want, ",") {
+		t.Errorf("expected answers %q, got %q", want, s.answers)
+	}
+}
+
+// checkErr fails the test unless err matches want, either with errors.Is or by containing its message
+func checkErr(t *testing.T, err, want error) {
+	t.Helper()
+	switch {
+	case want == nil && err != nil:
+		t.Fatalf("unexpected error: %v", err)
+	case want == nil:
+	case err == nil:
+		t.Fatalf("expected error %q, got nil", want)
+	case !errors.Is(err, want) && !strings.Contains(err.Error(), want.Error()):
+		t.Fatalf("expected error %q, got %q", want, err)
+	}
+}
@@ -0,0 +1,47 @@
+package prompt
+
+import (
+	"github.com/tcnksm/go-input"
+)
+
+// Terminal prompts the operator in the terminal, asking again until the answer is valid
+type Terminal struct {
+	ui *input.UI
+}
+
+func NewTerminal(ui *input.UI) *Terminal {
+	return &Terminal{ui: ui}
+}
+
+func (t *Terminal) Confirm(question string, def bool) (bool, error) {
+	d := "n"
+	if def {
+		d = "y"
+	}
+	answer, err := t.ui.Ask(question+" [y/n]", &input.Options{
+		Default:  d,
+		Loop:     true,
+		Required: true,
+		ValidateFunc: func(s string) error {
+			_, err := parseBool(s)
+			return err
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+	return parseBool(answer)
+}
+
+func (t *Terminal) Ask(question string, opts *Options) (string, error) {
+	o := &input.Options{Loop: true, ValidateFunc: opts.validate}
+	if opts != nil {
+		o.Default = opts.Default
+		o.Required = opts.Required
+	}
+	return t.ui.Ask(question, o)
+}
+
+func (t *Terminal) Select(question string, options []string) (string, error) {
+	return t.ui.Select(question, options, &input.Options{Required: true, Loop: true})
+}
@@ -0,0 +1,246 @@
+// Package provenance signs the job specs published to the Market with an operator's
+// Ethereum key, so anyone can check a listing was published by the oracle's owner.
+//
+// The signature is an EIP-191 personal signature, the same as eth_sign and
+// personal_sign, over the spec's canonical payload.
+package provenance
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"io/ioutil"
+	"market-sync/client"
+	"sort"
+)
+
+var (
+	ErrUnsigned         = errors.New("listing isn't signed")
+	ErrInvalidSignature = errors.New("invalid signature")
+)
+
+// payload is what's signed of a job spec, being everything that describes the job
+// to consumers of its listing
+type payload struct {
+	JobID      string                       `json:"jobId"`
+	NodeID     string                       `json:"nodeId"`
+	Name       string                       `json:"name"`
+	MinPayment string                       `json:"minPayment"`
+	Initiators []*client.ChainlinkInitiator `json:"initiators"`
+	Tasks      []*client.ChainlinkTaskSpec  `json:"tasks"`
+}
+
+// Canonicalize returns the spec's payload as compact JSON with every object's keys
+// sorted, so the same spec always gives the same bytes
+func Canonicalize(spec *client.ChainlinkJobSpec) ([]byte, error) {
+	p := &payload{
+		JobID:      client.NormaliseJobID(spec.ID),
+		Name:       spec.Name,
+		MinPayment: spec.MinPayment,
+		Initiators: spec.Attributes.Initiators,
+		Tasks:      spec.Attributes.Tasks,
+	}
+	if spec.NodeID != nil {
+		p.NodeID = spec.NodeID.String()
+	}
+	b, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+	// decoding into maps and encoding again sorts the keys of the params, with UseNumber
+	// so this doesn't change any numbers. The params already hold numbers as the float64
+	// they were decoded from the node as, so it's their float64 values that are signed.
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	var v interface{}
+	if err := d.Decode(&v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// Signer signs job specs with an operator's key. Sign is safe to call on a nil
+// *Signer, leaving specs unsigned.
+type Signer struct {
+	key *ecdsa.PrivateKey
+}
+
+func NewSigner(key *ecdsa.PrivateKey) *Signer {
+	return &Signer{key: key}
+}
+
+// LoadSigner decrypts a go-ethereum keystore file
+func LoadSigner(path, password string) (*Signer, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := keystore.DecryptKey(b, password)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decrypt keystore %s: %v", path, err)
+	}
+	return NewSigner(key.PrivateKey), nil
+}
+
+func (s *Signer) Address() common.Address {
+	return crypto.PubkeyToAddress(s.key.PublicKey)
+}
+
+// Sign sets the spec's provenance to a signature over its canonical payload, which
+// must be called after every other change to the spec
+func (s *Signer) Sign(spec *client.ChainlinkJobSpec) error {
+	if s == nil {
+		return nil
+	}
+	b, err := Canonicalize(spec)
+	if err != nil {
+		return err
+	}
+	sig, err := s.SignBytes(b)
+	if err != nil {
+		return err
+	}
+	spec.Provenance = &client.Provenance{
+		Payload:   string(b),
+		Signature: sig,
+		Signer:    s.Address(),
+	}
+	return nil
+}
+
+// SignBytes returns the hex encoded personal signature over b
+func (s *Signer) SignBytes(b []byte) (string, error) {
+	sig, err := crypto.Sign(accounts.TextHash(b), s.key)
+	if err != nil {
+		return "", err
+	}
+	// personal signatures use 27 and 28 as the recovery ID
+	sig[crypto.RecoveryIDOffset] += 27
+	return hexutil.Encode(sig), nil
+}
+
+// Recover returns the address that signed the provenance's payload, checking it's
+// the signer the provenance claims
+func Recover(p *client.Provenance) (common.Address, error) {
+	if p == nil {
+		return common.Address{}, ErrUnsigned
+	}
+	signer, err := RecoverBytes([]byte(p.Payload), p.Signature)
+	if err != nil {
+		return common.Address{}, err
+	} else if signer != p.Signer {
+		return signer, fmt.Errorf("%w: signed by %s, not %s", ErrInvalidSignature, signer.String(), p.Signer.String())
+	}
+	return signer, nil
+}
+
+// RecoverBytes returns the address that made the hex encoded personal signature over b
+func RecoverBytes(b []byte, signature string) (common.Address, error) {
+	sig, err := hexutil.Decode(signature)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	} else if len(sig) != crypto.SignatureLength {
+		return common.Address{}, fmt.Errorf("%w: expected %d bytes, got %d", ErrInvalidSignature, crypto.SignatureLength, len(sig))
+	}
+	if sig[crypto.RecoveryIDOffset] >= 27 {
+		sig[crypto.RecoveryIDOffset] -= 27
+	}
+	pub, err := crypto.SigToPub(accounts.TextHash(b), sig)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
+
+// Matches returns an error if the provenance's payload isn't for the given job and node
+func Matches(p *client.Provenance, jobId string, nodeId string) error {
+	var signed payload
+	if err := json.Unmarshal([]byte(p.Payload), &signed); err != nil {
+		return fmt.Errorf("%w: payload isn't a job spec: %v", ErrInvalidSignature, err)
+	}
+	if len(jobId) > 0 && signed.JobID != client.NormaliseJobID(jobId) {
+		return fmt.Errorf("%w: payload is for job spec %s, not %s", ErrInvalidSignature, signed.JobID, jobId)
+	}
+	if len(nodeId) > 0 && signed.NodeID != nodeId {
+		return fmt.Errorf("%w: payload is for Market node %s, not %s", ErrInvalidSignature, signed.NodeID, nodeId)
+	}
+	return nil
+}
+
+// MatchesListing returns an error if the Market job's provenance isn't for the job and
+// its node, or if the job's name, cost or tasks differ from the ones that were signed
+func MatchesListing(job *client.MarketJob) error {
+	if job.Provenance == nil {
+		return ErrUnsigned
+	} else if err := Matches(job.Provenance, job.NodeJobID, job.NodeID.String()); err != nil {
+		return err
+	}
+	var signed payload
+	if err := json.Unmarshal([]byte(job.Provenance.Payload), &signed); err != nil {
+		return fmt.Errorf("%w: payload isn't a job spec: %v", ErrInvalidSignature, err)
+	}
+	if job.Name != signed.Name {
+		return fmt.Errorf("%w: listing is named %q, but %q was signed", ErrInvalidSignature, job.Name, signed.Name)
+	}
+	if cost, err := client.ParseLink(job.Cost); err != nil {
+		return fmt.Errorf("%w: listing's cost %q is invalid: %v", ErrInvalidSignature, job.Cost, err)
+	} else if signedCost, err := client.ParseLink(signed.MinPayment); err != nil {
+		return fmt.Errorf("%w: signed cost %q is invalid: %v", ErrInvalidSignature, signed.MinPayment, err)
+	} else if cost.Cmp(signedCost) != 0 {
+		return fmt.Errorf("%w: listing costs %s, but %s was signed", ErrInvalidSignature, cost.Display(), signedCost.Display())
+	}
+	return matchesTasks(job.Tasks, signed.Tasks)
+}
+
+// matchesTasks compares the parameters of each listed task with the signed task at
+// the same index, as the Market lists tasks by adapter rather than by type
+func matchesTasks(listed []*client.MarketTask, signed []*client.ChainlinkTaskSpec) error {
+	if len(listed) != len(signed) {
+		return fmt.Errorf("%w: listing has %d tasks, but %d were signed", ErrInvalidSignature, len(listed), len(signed))
+	}
+	tasks := make([]*client.MarketTask, len(listed))
+	copy(tasks, listed)
+	sort.SliceStable(tasks, func(i, j int) bool { return tasks[i].Index < tasks[j].Index })
+	for i, task := range tasks {
+		params := map[string][]interface{}{}
+		for _, p := range task.Param {
+			params[p.Key] = p.Values
+		}
+		if len(params) != len(signed[i].Params) {
+			return fmt.Errorf(
+				"%w: listing's task %d has %d parameters, but %d were signed",
+				ErrInvalidSignature, i, len(params), len(signed[i].Params),
+			)
+		}
+		for key, value := range signed[i].Params {
+			// a single value is listed as a list of one value
+			values, ok := value.([]interface{})
+			if !ok {
+				values = []interface{}{value}
+			}
+			listed, ok := params[key]
+			if !ok {
+				return fmt.Errorf("%w: listing's task %d has no %s parameter, but it was signed", ErrInvalidSignature, i, key)
+			}
+			a, err := json.Marshal(listed)
+			if err != nil {
+				return err
+			}
+			b, err := json.Marshal(values)
+			if err != nil {
+				return err
+			}
+			if !bytes.Equal(a, b) {
+				return fmt.Errorf("%w: listing's task %d has %s %s, but %s was signed", ErrInvalidSignature, i, key, a, b)
+			}
+		}
+	}
+	return nil
+}
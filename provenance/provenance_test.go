@@ -0,0 +1,74 @@
+package provenance
+
+import (
+	"errors"
+	"github.com/ethereum/go-ethereum/crypto"
+	uuid "github.com/satori/go.uuid"
+	"market-sync/client"
+	"testing"
+)
+
+// signedListing signs a spec and returns the Market job it would be listed as
+func signedListing(t *testing.T) *client.MarketJob {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodeId := uuid.NewV4()
+	spec := &client.ChainlinkJobSpec{ID: "A1B2-C3D4", Name: "eth-usd", NodeID: &nodeId, MinPayment: "100000000000000000"}
+	spec.Attributes.Tasks = []*client.ChainlinkTaskSpec{
+		{Type: "httpget", Params: map[string]interface{}{"get": "https://example.com/price"}},
+		{Type: "jsonparse", Params: map[string]interface{}{"path": []interface{}{"data", "price"}}},
+		{Type: "multiply", Params: map[string]interface{}{"times": 100}},
+	}
+	if err := NewSigner(key).Sign(spec); err != nil {
+		t.Fatal(err)
+	}
+	return &client.MarketJob{
+		ID:        uuid.NewV4(),
+		Name:      "eth-usd",
+		NodeID:    nodeId,
+		NodeJobID: "a1b2c3d4",
+		Cost:      "0.1 LINK",
+		Tasks: []*client.MarketTask{
+			{Index: 1, Param: []*client.MarketTaskParam{{Key: "path", Values: []interface{}{"data", "price"}}}},
+			{Index: 0, Param: []*client.MarketTaskParam{{Key: "get", Values: []interface{}{"https://example.com/price"}}}},
+			{Index: 2, Param: []*client.MarketTaskParam{{Key: "times", Values: []interface{}{float64(100)}}}},
+		},
+		Provenance: spec.Provenance,
+	}
+}
+
+func TestMatchesListing(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(job *client.MarketJob)
+		want   error
+	}{
+		{"as signed", func(*client.MarketJob) {}, nil},
+		{"unsigned", func(job *client.MarketJob) { job.Provenance = nil }, ErrUnsigned},
+		{"other job", func(job *client.MarketJob) { job.NodeJobID = "e5f6" }, ErrInvalidSignature},
+		{"other node", func(job *client.MarketJob) { job.NodeID = uuid.NewV4() }, ErrInvalidSignature},
+		{"renamed", func(job *client.MarketJob) { job.Name = "btc-usd" }, ErrInvalidSignature},
+		{"repriced", func(job *client.MarketJob) { job.Cost = "1 LINK" }, ErrInvalidSignature},
+		{"task removed", func(job *client.MarketJob) { job.Tasks = job.Tasks[:2] }, ErrInvalidSignature},
+		{"param changed", func(job *client.MarketJob) {
+			job.Tasks[1].Param[0].Values = []interface{}{"https://attacker.example/price"}
+		}, ErrInvalidSignature},
+		{"param added", func(job *client.MarketJob) {
+			job.Tasks[2].Param = append(job.Tasks[2].Param, &client.MarketTaskParam{Key: "extra", Values: []interface{}{1}})
+		}, ErrInvalidSignature},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			job := signedListing(t)
+			test.modify(job)
+			err := MatchesListing(job)
+			if test.want == nil && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			} else if test.want != nil && !errors.Is(err, test.want) {
+				t.Errorf("expected %v, got %v", test.want, err)
+			}
+		})
+	}
+}
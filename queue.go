@@ -0,0 +1,299 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	uuid "github.com/satori/go.uuid"
+	"io"
+	"io/ioutil"
+	"market-sync/client"
+	"market-sync/provenance"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PublishRequest is a job spec proposed to be published to the Market, waiting on
+// approval by a different operator. The spec is final, with its name, cost and any edits.
+//
+// Anyone who can write to the queue can claim to be any proposer, so each request is
+// signed with the proposer's key. Proposer is the address that signed it, which the
+// approver checks is one of the operators trusted to propose job specs.
+type PublishRequest struct {
+	ID         string                   `json:"id"`
+	Spec       *client.ChainlinkJobSpec `json:"spec"`
+	NetworkID  int                      `json:"networkId"`
+	ProposedBy uuid.UUID                `json:"proposedBy"`
+	ProposedAt time.Time                `json:"proposedAt"`
+	Proposer   common.Address           `json:"proposer"`
+	Signature  string                   `json:"signature"`
+}
+
+var (
+	// ErrNoProposer is returned for publish requests without the Market user that proposed
+	// them, as they can't be checked against the approver
+	ErrNoProposer = errors.New("publish request has no proposer, so can't be approved by a different operator")
+	// ErrUnsignedRequest is returned for publish requests that aren't signed by their proposer
+	ErrUnsignedRequest = errors.New("publish request isn't signed by its proposer")
+)
+
+// Validate checks the request has everything needed for it to be approved, and is
+// signed by the proposer it claims
+func (r *PublishRequest) Validate() error {
+	if len(r.ID) == 0 || r.Spec == nil {
+		return errors.New("publish request must have an id and spec")
+	} else if uuid.Equal(r.ProposedBy, uuid.Nil) {
+		return ErrNoProposer
+	} else if len(r.Signature) == 0 || r.Proposer == (common.Address{}) {
+		return ErrUnsignedRequest
+	}
+	b, err := r.payload()
+	if err != nil {
+		return err
+	}
+	signer, err := provenance.RecoverBytes(b, r.Signature)
+	if err != nil {
+		return err
+	} else if signer != r.Proposer {
+		return fmt.Errorf("%w: signed by %s, not %s", provenance.ErrInvalidSignature, signer.String(), r.Proposer.String())
+	}
+	return nil
+}
+
+// Sign sets the request's proposer to the signer, and signs the request
+func (r *PublishRequest) Sign(s *provenance.Signer) error {
+	if s == nil {
+		return ErrUnsignedRequest
+	}
+	r.Proposer = s.Address()
+	b, err := r.payload()
+	if err != nil {
+		return err
+	}
+	r.Signature, err = s.SignBytes(b)
+	return err
+}
+
+// payload is what's signed of the request, being everything but the signature, with
+// the spec's canonical payload so it's the same after being read back from the queue
+func (r *PublishRequest) payload() ([]byte, error) {
+	spec, err := provenance.Canonicalize(r.Spec)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(struct {
+		ID         string          `json:"id"`
+		Spec       json.RawMessage `json:"spec"`
+		NetworkID  int             `json:"networkId"`
+		ProposedBy uuid.UUID       `json:"proposedBy"`
+		ProposedAt time.Time       `json:"proposedAt"`
+		Proposer   common.Address  `json:"proposer"`
+	}{r.ID, spec, r.NetworkID, r.ProposedBy, r.ProposedAt, r.Proposer})
+}
+
+// Queue holds the publish requests waiting for approval
+type Queue interface {
+	Add(r *PublishRequest) error
+	List() ([]*PublishRequest, error)
+	Remove(id string) error
+}
+
+// FileQueue stores the queue as a JSON file
+type FileQueue struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewFileQueue(path string) *FileQueue {
+	return &FileQueue{path: path}
+}
+
+func (q *FileQueue) Add(r *PublishRequest) error {
+	if err := r.Validate(); err != nil {
+		return err
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	requests, err := q.read()
+	if err != nil {
+		return err
+	}
+	for _, e := range requests {
+		if e.ID == r.ID {
+			return fmt.Errorf("publish request %s already exists", r.ID)
+		}
+	}
+	return q.write(append(requests, r))
+}
+
+func (q *FileQueue) List() ([]*PublishRequest, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.read()
+}
+
+func (q *FileQueue) Remove(id string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	requests, err := q.read()
+	if err != nil {
+		return err
+	}
+	var kept []*PublishRequest
+	for _, r := range requests {
+		if r.ID != id {
+			kept = append(kept, r)
+		}
+	}
+	if len(kept) == len(requests) {
+		return fmt.Errorf("publish request %s not found", id)
+	}
+	return q.write(kept)
+}
+
+func (q *FileQueue) read() ([]*PublishRequest, error) {
+	b, err := ioutil.ReadFile(q.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var requests []*PublishRequest
+	if err := json.Unmarshal(b, &requests); err != nil {
+		return nil, fmt.Errorf("invalid queue file %s: %v", q.path, err)
+	}
+	return requests, nil
+}
+
+// write replaces the file in a single rename, so it's never left partially written
+func (q *FileQueue) write(requests []*PublishRequest) error {
+	if requests == nil {
+		requests = []*PublishRequest{}
+	}
+	b, err := json.MarshalIndent(requests, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(q.path), ".market-sync-queue-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		_ = tmp.Close()
+		return err
+	} else if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), q.path)
+}
+
+// HTTPQueue uses a queue served by `market-sync queue serve`
+type HTTPQueue struct {
+	url    string
+	client *http.Client
+}
+
+func NewHTTPQueue(url string) *HTTPQueue {
+	return &HTTPQueue{url: strings.TrimRight(url, "/"), client: &http.Client{Timeout: 30 * time.Second}}
+}
+
+func (q *HTTPQueue) Add(r *PublishRequest) error {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	return q.do(http.MethodPost, "/requests", bytes.NewBuffer(b), http.StatusCreated, nil)
+}
+
+func (q *HTTPQueue) List() ([]*PublishRequest, error) {
+	var requests []*PublishRequest
+	err := q.do(http.MethodGet, "/requests", nil, http.StatusOK, &requests)
+	return requests, err
+}
+
+func (q *HTTPQueue) Remove(id string) error {
+	return q.do(http.MethodDelete, fmt.Sprintf("/requests/%s", id), nil, http.StatusOK, nil)
+}
+
+func (q *HTTPQueue) do(method, endpoint string, body io.Reader, code int, obj interface{}) error {
+	req, err := http.NewRequest(method, q.url+endpoint, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := q.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	} else if resp.StatusCode != code {
+		return fmt.Errorf(
+			"queue: unexpected response code, got %d, expected %d when calling %s: %s",
+			resp.StatusCode,
+			code,
+			endpoint,
+			strings.TrimSpace(string(b)),
+		)
+	} else if obj == nil {
+		return nil
+	}
+	return json.Unmarshal(b, obj)
+}
+
+// QueueHandler serves the queue over HTTP for HTTPQueue. It's unauthenticated, so
+// anyone who can reach it can add and remove requests, and must only be served on localhost.
+func QueueHandler(q Queue) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/requests", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			requests, err := q.List()
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			if requests == nil {
+				requests = []*PublishRequest{}
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(requests)
+		case http.MethodPost:
+			pr := &PublishRequest{}
+			if err := json.NewDecoder(r.Body).Decode(pr); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			} else if err := pr.Validate(); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			} else if err := q.Add(pr); err != nil {
+				http.Error(w, err.Error(), http.StatusConflict)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+	mux.HandleFunc("/requests/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if err := q.Remove(strings.TrimPrefix(r.URL.Path, "/requests/")); err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	return mux
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"errors"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	uuid "github.com/satori/go.uuid"
+	"io/ioutil"
+	"market-sync/client"
+	"market-sync/provenance"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// signedRequest returns a publish request signed by a new key
+func signedRequest(t *testing.T) *PublishRequest {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec := &client.ChainlinkJobSpec{ID: "A1B2-C3D4", Name: "eth-usd", MinPayment: "100000000000000000"}
+	spec.Attributes.Tasks = []*client.ChainlinkTaskSpec{
+		{Type: "httpget", Params: map[string]interface{}{"get": "https://example.com/price"}},
+		{Type: "multiply", Params: map[string]interface{}{"times": 100}},
+	}
+	r := &PublishRequest{
+		ID:         uuid.NewV4().String(),
+		Spec:       spec,
+		NetworkID:  1,
+		ProposedBy: uuid.NewV4(),
+		ProposedAt: time.Now(),
+	}
+	if err := r.Sign(provenance.NewSigner(key)); err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestPublishRequest_Validate(t *testing.T) {
+	other, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		modify func(r *PublishRequest)
+		want   error
+	}{
+		{"signed", func(r *PublishRequest) {}, nil},
+		{"unsigned", func(r *PublishRequest) { r.Signature = "" }, ErrUnsignedRequest},
+		{"no proposer key", func(r *PublishRequest) { r.Proposer = common.Address{} }, ErrUnsignedRequest},
+		{"no market user", func(r *PublishRequest) { r.ProposedBy = uuid.Nil }, ErrNoProposer},
+		{"claimed proposer", func(r *PublishRequest) { r.Proposer = crypto.PubkeyToAddress(other.PublicKey) }, provenance.ErrInvalidSignature},
+		{"edited cost", func(r *PublishRequest) { r.Spec.MinPayment = "1" }, provenance.ErrInvalidSignature},
+		{"edited task", func(r *PublishRequest) { r.Spec.Attributes.Tasks[0].Params["get"] = "https://example.org" }, provenance.ErrInvalidSignature},
+		{"edited network", func(r *PublishRequest) { r.NetworkID = 3 }, provenance.ErrInvalidSignature},
+		{"edited market user", func(r *PublishRequest) { r.ProposedBy = uuid.NewV4() }, provenance.ErrInvalidSignature},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := signedRequest(t)
+			test.modify(r)
+			if err := r.Validate(); !errors.Is(err, test.want) {
+				t.Fatalf("expected %v, got %v", test.want, err)
+			}
+		})
+	}
+}
+
+func TestPublishRequest_SignNil(t *testing.T) {
+	if err := (&PublishRequest{}).Sign(nil); !errors.Is(err, ErrUnsignedRequest) {
+		t.Fatalf("expected %v, got %v", ErrUnsignedRequest, err)
+	}
+}
+
+func TestFileQueue_SignedRoundTrip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "market-sync-queue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	q := NewFileQueue(filepath.Join(dir, "queue.json"))
+
+	r := signedRequest(t)
+	if err := q.Add(r); err != nil {
+		t.Fatal(err)
+	}
+	requests, err := q.List()
+	if err != nil {
+		t.Fatal(err)
+	} else if len(requests) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(requests))
+	}
+	if err := requests[0].Validate(); err != nil {
+		t.Fatalf("expected the request to still validate after being read back, got %v", err)
+	} else if requests[0].Proposer != r.Proposer {
+		t.Fatalf("expected proposer %s, got %s", r.Proposer.String(), requests[0].Proposer.String())
+	}
+
+	unsigned := signedRequest(t)
+	unsigned.Signature = ""
+	if err := q.Add(unsigned); !errors.Is(err, ErrUnsignedRequest) {
+		t.Fatalf("expected %v, got %v", ErrUnsignedRequest, err)
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"market-sync/client"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var secretKeyMatcher = regexp.MustCompile(
+	`(?i)(api[_-]?key|secret|token|passw(or)?d|auth|access[_-]?key|private[_-]?key|credential)`,
+)
+
+// detectSecrets returns a description of every task parameter that looks like it
+// contains a secret, either by its name or within a URL's credentials or query
+func detectSecrets(spec *client.ChainlinkJobSpec) []string {
+	var found []string
+	for i, t := range spec.Attributes.Tasks {
+		walkParams(t.Params, nil, func(path []string) {
+			v, _ := getParam(t.Params, path)
+			s, ok := v.(string)
+			if !ok || len(s) == 0 {
+				return
+			}
+			name := fmt.Sprintf("task %d (%s) %s", i, t.Type, strings.Join(path, "."))
+			if secretKeyMatcher.MatchString(strings.Join(path, ".")) {
+				found = append(found, fmt.Sprintf("%s: parameter name looks like a secret", name))
+			}
+			u, err := url.Parse(s)
+			if err != nil || len(u.Host) == 0 {
+				return
+			}
+			if _, ok := u.User.Password(); ok {
+				found = append(found, fmt.Sprintf("%s: URL contains a password", name))
+			}
+			for key := range u.Query() {
+				if secretKeyMatcher.MatchString(key) {
+					found = append(found, fmt.Sprintf("%s: URL query parameter %s looks like a secret", name, key))
+				}
+			}
+		})
+	}
+	sort.Strings(found)
+	return found
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"io"
+	"market-sync/client"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+const (
+	StatusOutputFlag  = "output"
+	StatusOutputTable = "table"
+	StatusOutputJSON  = "json"
+
+	// runStatsSampleSize is how many of a job's most recent runs its stats are aggregated from
+	runStatsSampleSize = 100
+)
+
+func generateStatusCmd() *cobra.Command {
+	newcmd := &cobra.Command{
+		Use:   "status",
+		Short: "List every job on the Market for the node, along with its status on the node",
+		Args:  cobra.MaximumNArgs(0),
+		Run:   runStatus,
+	}
+	newcmd.Flags().String(StatusOutputFlag, StatusOutputTable, "output format: table or json")
+	return newcmd
+}
+
+func runStatus(_ *cobra.Command, _ []string) {
+	requireFlags(append(nodeFlags, marketFlags...)...)
+	a, err := NewApplication(newConfig(&Config{}))
+	if err != nil {
+		exit(err)
+	}
+	plan, err := a.syncer.Plan()
+	if err != nil {
+		exit(err)
+	}
+	statuses, err := a.JobStatuses(plan.Nodes())
+	if err != nil {
+		exit(err)
+	}
+	if err := WriteJobStatuses(os.Stdout, statuses, viper.GetString(StatusOutputFlag)); err != nil {
+		exit(err)
+	}
+}
+
+// JobStatus is a job listed on the Market for the node, joined with the job spec on the node
+type JobStatus struct {
+	MarketJobID string `json:"marketJobId"`
+	// MarketNodeID is the Market node the job is listed under
+	MarketNodeID string   `json:"marketNodeId"`
+	NodeJobID    string   `json:"nodeJobId"`
+	Name         string   `json:"name"`
+	Cost         string   `json:"cost"`
+	Tasks        []string `json:"tasks"`
+	OnNode       bool     `json:"onNode"`
+	// Changed is whether the job spec on the node has changed since it was published
+	Changed bool `json:"changed"`
+
+	Stats *client.JobRunStats `json:"stats,omitempty"`
+}
+
+// JobStatuses returns every job listed on the Market nodes, which are the Market
+// nodes of each oracle the Chainlink node fulfils requests for
+func (a *Application) JobStatuses(nodes []*client.MarketNode) ([]*JobStatus, error) {
+	specs, err := a.nodeJobSpecs()
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []*JobStatus
+	for _, node := range nodes {
+		if statuses, err = a.nodeJobStatuses(node, specs, statuses); err != nil {
+			return nil, err
+		}
+	}
+	return statuses, nil
+}
+
+// nodeJobStatuses appends the status of every job listed under the Market node
+func (a *Application) nodeJobStatuses(
+	node *client.MarketNode,
+	specs map[string]*client.ChainlinkJobSpec,
+	statuses []*JobStatus,
+) ([]*JobStatus, error) {
+	it := client.NewJobIterator(a.market.Jobs, node.ID, a.config.PageSize)
+	for it.Next() {
+		j := it.Job()
+		s := &JobStatus{
+			MarketJobID:  j.ID.String(),
+			MarketNodeID: node.ID.String(),
+			NodeJobID:    j.NodeJobID,
+			Name:         j.Name,
+			Cost:         j.Cost,
+		}
+		if spec, ok := specs[client.NormaliseJobID(j.NodeJobID)]; ok {
+			s.OnNode = true
+			s.Tasks = taskTypes(spec)
+			if len(j.Fingerprint) > 0 {
+				fingerprint, err := spec.ContentHash()
+				if err != nil {
+					return nil, err
+				}
+				s.Changed = fingerprint != j.Fingerprint
+			}
+			if stats, err := a.chainlink.SpecRunStats(spec.ID, runStatsSampleSize); err != nil {
+				color.Red("Warning: unable to read the runs of job %s, its run stats won't be listed", spec.ID)
+				displayError(err)
+			} else {
+				s.Stats = stats
+			}
+		}
+		statuses = append(statuses, s)
+	}
+	return statuses, it.Err()
+}
+
+// nodeJobSpecs returns every job spec on the node, keyed by their normalised ID
+func (a *Application) nodeJobSpecs() (map[string]*client.ChainlinkJobSpec, error) {
+	specs := map[string]*client.ChainlinkJobSpec{}
+	it := client.NewSpecIterator(a.chainlink.GetSpecs, a.config.PageSize)
+	for it.Next() {
+		spec := it.Spec()
+		specs[client.NormaliseJobID(spec.ID)] = spec
+	}
+	return specs, it.Err()
+}
+
+func WriteJobStatuses(w io.Writer, statuses []*JobStatus, output string) error {
+	switch output {
+	case StatusOutputJSON:
+		e := json.NewEncoder(w)
+		e.SetIndent("", "  ")
+		return e.Encode(statuses)
+	case StatusOutputTable:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintf(tw, "NAME\tCOST\tTASKS\tON NODE\tCHANGED\tRUNS\tSUCCESS\tAVG LATENCY\tLAST RUN\tNODE JOB ID\tMARKET JOB ID\tMARKET NODE ID\t\n")
+		for _, s := range statuses {
+			cost := s.Cost
+			if c, err := client.ParseLink(s.Cost); err == nil {
+				cost = c.LinkString()
+			}
+			onNode, changed := "no", "-"
+			if s.OnNode {
+				onNode = "yes"
+			}
+			if s.Changed {
+				changed = "yes"
+			}
+			runs, success, latency, lastRun := "-", "-", "-", "-"
+			if s.Stats != nil {
+				runs = strconv.Itoa(s.Stats.Total)
+				if s.Stats.Completed+s.Stats.Errored > 0 {
+					success = fmt.Sprintf("%.1f%%", s.Stats.SuccessRate*100)
+				}
+				if s.Stats.AverageLatencyMs > 0 {
+					latency = s.Stats.AverageLatency().String()
+				}
+				if s.Stats.LastRunAt != nil {
+					lastRun = s.Stats.LastRunAt.Format(time.RFC3339)
+				}
+			}
+			_, _ = fmt.Fprintf(
+				tw,
+				"%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t\n",
+				s.Name,
+				cost,
+				strings.Join(s.Tasks, ","),
+				onNode,
+				changed,
+				runs,
+				success,
+				latency,
+				lastRun,
+				s.NodeJobID,
+				s.MarketJobID,
+				s.MarketNodeID,
+			)
+		}
+		return tw.Flush()
+	default:
+		return fmt.Errorf("unknown output format %q, must be %s or %s", output, StatusOutputTable, StatusOutputJSON)
+	}
+}
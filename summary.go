@@ -0,0 +1,146 @@
+package main
+
+import (
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/fatih/color"
+	"io"
+	"market-sync/client"
+	"market-sync/syncer"
+	"text/tabwriter"
+)
+
+type SpecOutcome string
+
+const (
+	// OutcomePending is a job spec the sync didn't reach
+	OutcomePending   SpecOutcome = "pending"
+	OutcomePublished SpecOutcome = "published"
+	OutcomeProposed  SpecOutcome = "proposed"
+	OutcomeSkipped   SpecOutcome = "skipped"
+	OutcomeFailed    SpecOutcome = "failed"
+)
+
+// SpecResult is the outcome of syncing a single job spec
+type SpecResult struct {
+	SpecID  string
+	Name    string
+	Outcome SpecOutcome
+	Err     error
+}
+
+// SyncSummary is the outcome of every job spec in a sync, in the order they were synced
+type SyncSummary struct {
+	results []*SpecResult
+	index   map[string]*SpecResult
+
+	// Balances are the node's balances checked before syncing, if they were checked
+	Balances *syncer.BalanceCheck
+}
+
+// NewSyncSummary starts every job spec as pending
+func NewSyncSummary(specs []*client.ChainlinkJobSpec) *SyncSummary {
+	s := &SyncSummary{index: map[string]*SpecResult{}}
+	for _, spec := range specs {
+		r := &SpecResult{SpecID: spec.ID, Outcome: OutcomePending}
+		s.results = append(s.results, r)
+		s.index[spec.ID] = r
+	}
+	return s
+}
+
+// Record sets the outcome of the job spec, replacing any earlier outcome such as a failure that was retried
+func (s *SyncSummary) Record(spec *client.ChainlinkJobSpec, outcome SpecOutcome, err error) {
+	r, ok := s.index[spec.ID]
+	if !ok {
+		r = &SpecResult{SpecID: spec.ID}
+		s.results = append(s.results, r)
+		s.index[spec.ID] = r
+	}
+	r.Name, r.Outcome, r.Err = spec.Name, outcome, err
+}
+
+func (s *SyncSummary) Count(outcome SpecOutcome) int {
+	var count int
+	for _, r := range s.results {
+		if r.Outcome == outcome {
+			count++
+		}
+	}
+	return count
+}
+
+// Err returns a PartialSyncError if any of the job specs failed
+func (s *SyncSummary) Err() error {
+	failed := s.Count(OutcomeFailed)
+	if failed == 0 {
+		return nil
+	}
+	return &PartialSyncError{Synced: s.Count(OutcomePublished) + s.Count(OutcomeProposed), Failed: failed}
+}
+
+// Write writes the outcome counts, followed by every job spec that didn't succeed
+func (s *SyncSummary) Write(w io.Writer) error {
+	if len(s.results) == 0 {
+		return nil
+	}
+	yellow := color.New(color.FgYellow).SprintFunc()
+	_, _ = fmt.Fprintf(w, "\n%s\n", yellow("Sync Summary:"))
+	for _, o := range []SpecOutcome{OutcomePublished, OutcomeProposed, OutcomeSkipped, OutcomeFailed, OutcomePending} {
+		if c := s.Count(o); c > 0 || o == OutcomePublished || o == OutcomeFailed {
+			_, _ = fmt.Fprintf(w, "  %-10s %d\n", o, c)
+		}
+	}
+	if err := s.writeBalances(w); err != nil {
+		return err
+	}
+	if s.Count(OutcomeFailed)+s.Count(OutcomePending) == 0 {
+		return nil
+	}
+
+	_, _ = fmt.Fprintln(w)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintf(tw, "JOB ID\tNAME\tOUTCOME\tERROR\t\n")
+	for _, r := range s.results {
+		if r.Outcome != OutcomeFailed && r.Outcome != OutcomePending {
+			continue
+		}
+		name, msg := r.Name, "-"
+		if len(name) == 0 {
+			name = "-"
+		}
+		if r.Err != nil {
+			msg = r.Err.Error()
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t\n", r.SpecID, name, r.Outcome, msg)
+	}
+	return tw.Flush()
+}
+
+// writeBalances writes the balance of each of the node's keys, and whether it's below the minimum
+func (s *SyncSummary) writeBalances(w io.Writer) error {
+	if s.Balances == nil {
+		return nil
+	}
+	yellow := color.New(color.FgYellow).SprintFunc()
+	_, _ = fmt.Fprintf(w, "\n%s (from the %s, minimum %s)\n", yellow("Node Balances:"), s.Balances.Source, s.Balances.Minimum.String())
+	low := map[common.Address]bool{}
+	for _, k := range s.Balances.Low() {
+		low[k.Address] = true
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintf(tw, "  ADDRESS\tETH\tLINK\tSTATUS\t\n")
+	for _, k := range s.Balances.Keys {
+		link, status := "-", "ok"
+		if k.LinkBalance != nil {
+			link = k.LinkBalance.LinkString()
+		}
+		if low[k.Address] {
+			status = "low"
+		} else if s.Balances.Account != (common.Address{}) && k.Address != s.Balances.Account {
+			status = "-"
+		}
+		_, _ = fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\t\n", k.Address.String(), k.ETHBalance.String(), link, status)
+	}
+	return tw.Flush()
+}
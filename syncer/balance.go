@@ -0,0 +1,112 @@
+package syncer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"market-sync/chain"
+	"market-sync/client"
+)
+
+var ErrLowBalance = errors.New("fulfillment account is low on ETH")
+
+const (
+	BalanceSourceNode = "node"
+	BalanceSourceRPC  = "rpc"
+)
+
+// BalanceCheck is the ETH and LINK balance of each of the node's keys, checked
+// against the minimum ETH balance needed to fulfil requests
+type BalanceCheck struct {
+	Keys []*client.ChainlinkETHKey
+	// Account is the node's fulfillment account, if it reports one
+	Account common.Address
+	// Minimum is the ETH balance below which requests may fail, no minimum if nil
+	Minimum *client.Eth
+	// Source is where the balances were read from, the node or the RPC
+	Source string
+}
+
+// Low returns the fulfillment account if it's below the minimum, or every key
+// below the minimum if the node doesn't report its fulfillment account
+func (c *BalanceCheck) Low() []*client.ChainlinkETHKey {
+	if c.Minimum == nil {
+		return nil
+	}
+	var low []*client.ChainlinkETHKey
+	for _, k := range c.Keys {
+		if c.Account != (common.Address{}) && k.Address != c.Account {
+			continue
+		}
+		if k.ETHBalance == nil || k.ETHBalance.Cmp(c.Minimum) < 0 {
+			low = append(low, k)
+		}
+	}
+	return low
+}
+
+// Err returns ErrLowBalance if any account is below the minimum
+func (c *BalanceCheck) Err() error {
+	low := c.Low()
+	if len(low) == 0 {
+		return nil
+	}
+	return fmt.Errorf(
+		"%w: %s has %s, below the minimum of %s",
+		ErrLowBalance,
+		low[0].Address.String(),
+		low[0].ETHBalance.String(),
+		c.Minimum.String(),
+	)
+}
+
+// CheckBalances reads the balances of the node's keys from the node, falling back
+// to reading them through the backend if the node can't report them and it's set
+func (s *Syncer) CheckBalances(ctx context.Context, backend chain.Backend, minimum *client.Eth) (*BalanceCheck, error) {
+	cfg, err := s.NodeConfig()
+	if err != nil {
+		return nil, err
+	}
+	attrs := cfg.Data.Attributes
+	check := &BalanceCheck{Minimum: minimum, Source: BalanceSourceNode}
+	if common.IsHexAddress(attrs.AccountAddress) {
+		check.Account = common.HexToAddress(attrs.AccountAddress)
+	}
+
+	balances, err := s.chainlink.Balances()
+	if err == nil {
+		for _, d := range balances.Data {
+			key := d.Attributes
+			if key.Address == (common.Address{}) && common.IsHexAddress(d.ID) {
+				key.Address = common.HexToAddress(d.ID)
+			}
+			check.Keys = append(check.Keys, &key)
+		}
+		return check, nil
+	} else if backend == nil {
+		return nil, err
+	}
+
+	keys, err := s.chainlink.ETHKeys()
+	if err != nil {
+		return nil, err
+	}
+	var link common.Address
+	if common.IsHexAddress(attrs.LinkContractAddress) {
+		link = common.HexToAddress(attrs.LinkContractAddress)
+	}
+	read, err := chain.ReadBalances(ctx, backend, link, keys.Addresses())
+	if err != nil {
+		return nil, err
+	}
+	check.Source = BalanceSourceRPC
+	for _, b := range read {
+		key := &client.ChainlinkETHKey{Address: b.Address, ETHBalance: (*client.Eth)(b.ETH)}
+		if b.LINK != nil {
+			key.LinkBalance = (*client.Link)(b.LINK)
+		}
+		check.Keys = append(check.Keys, key)
+	}
+	return check, nil
+}
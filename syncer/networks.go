@@ -0,0 +1,79 @@
+package syncer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+)
+
+var (
+	ErrUnknownNetwork  = errors.New("unknown network")
+	ErrNetworkMismatch = errors.New("network mismatch")
+)
+
+// Network maps an Ethereum chain ID to the Market's network
+type Network struct {
+	ChainID  int    `json:"chainId"`
+	MarketID int    `json:"marketId"`
+	Name     string `json:"name"`
+}
+
+// DefaultNetworks are the networks listed on the Market
+var DefaultNetworks = []*Network{
+	{ChainID: 1, MarketID: 1, Name: "mainnet"},
+	{ChainID: 3, MarketID: 3, Name: "ropsten"},
+	{ChainID: 4, MarketID: 4, Name: "rinkeby"},
+	{ChainID: 42, MarketID: 42, Name: "kovan"},
+}
+
+// NetworkRegistry looks up the Market network of a chain ID
+type NetworkRegistry struct {
+	networks map[int]*Network
+}
+
+// NewNetworkRegistry creates a registry of the default networks, with the given
+// networks added or replacing the default for their chain ID
+func NewNetworkRegistry(overrides ...*Network) *NetworkRegistry {
+	r := &NetworkRegistry{networks: map[int]*Network{}}
+	for _, n := range DefaultNetworks {
+		r.networks[n.ChainID] = n
+	}
+	for _, n := range overrides {
+		r.networks[n.ChainID] = n
+	}
+	return r
+}
+
+// LoadNetworks reads networks from a JSON file, eg:
+//
+//	[{"chainId": 100, "marketId": 7, "name": "xdai"}]
+func LoadNetworks(path string) ([]*Network, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var networks []*Network
+	if err := json.Unmarshal(b, &networks); err != nil {
+		return nil, fmt.Errorf("invalid networks file %s: %v", path, err)
+	}
+	for _, n := range networks {
+		if n.ChainID <= 0 || n.MarketID <= 0 {
+			return nil, fmt.Errorf("invalid networks file %s: every network needs a chainId and marketId", path)
+		}
+	}
+	return networks, nil
+}
+
+// Lookup returns the Market network of the chain ID
+func (r *NetworkRegistry) Lookup(chainId int) (*Network, error) {
+	n, ok := r.networks[chainId]
+	if !ok {
+		return nil, fmt.Errorf(
+			"%w: chain ID %d has no Market network, add it to the networks file",
+			ErrUnknownNetwork,
+			chainId,
+		)
+	}
+	return n, nil
+}
@@ -0,0 +1,91 @@
+package syncer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"market-sync/client"
+)
+
+var ErrNodeExists = errors.New("Chainlink node is already on the Market, use node update to change its details")
+
+// NodeMetadata are the details of the Market node shown on its listing
+type NodeMetadata struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Website     string `json:"website"`
+}
+
+// LoadNodeMetadata reads the node's details from a JSON file, eg:
+//
+//	{"name": "LinkPool", "description": "...", "website": "https://linkpool.io"}
+func LoadNodeMetadata(path string) (*NodeMetadata, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	m := &NodeMetadata{}
+	if err := json.Unmarshal(b, m); err != nil {
+		return nil, fmt.Errorf("invalid node file %s: %v", path, err)
+	} else if len(m.Name) == 0 {
+		return nil, fmt.Errorf("invalid node file %s: the node needs a name", path)
+	}
+	return m, nil
+}
+
+// RegisterNode creates the Market node for the default oracle address on the
+// Chainlink node's network, returning the ID of the new node
+func (s *Syncer) RegisterNode(nodes MarketNodeAPI, metadata *NodeMetadata) (*client.MarketCreated, error) {
+	if _, err := s.Node(); err == nil {
+		return nil, ErrNodeExists
+	} else if err != ErrNodeNotFound {
+		return nil, err
+	}
+	req, err := s.nodeRequest(metadata)
+	if err != nil {
+		return nil, err
+	}
+	created, err := nodes.CreateNode(req)
+	if err != nil {
+		return nil, err
+	}
+	// forget the node wasn't found, so it's looked up again
+	delete(s.nodes, req.OracleAddress)
+	return created, nil
+}
+
+// UpdateNode replaces the details of the Market node for the default oracle address
+func (s *Syncer) UpdateNode(nodes MarketNodeAPI, metadata *NodeMetadata) (*client.MarketNode, error) {
+	node, err := s.Node()
+	if err != nil {
+		return nil, err
+	}
+	req, err := s.nodeRequest(metadata)
+	if err != nil {
+		return nil, err
+	}
+	if err := nodes.UpdateNode(node.ID, req); err != nil {
+		return nil, err
+	}
+	node.Name, node.Description, node.Website = req.Name, req.Description, req.Website
+	return node, nil
+}
+
+func (s *Syncer) nodeRequest(metadata *NodeMetadata) (*client.MarketNodeRequest, error) {
+	oracle, err := s.Oracle()
+	if err != nil {
+		return nil, err
+	}
+	network, err := s.Network()
+	if err != nil {
+		return nil, err
+	}
+	return &client.MarketNodeRequest{
+		Name:          metadata.Name,
+		Description:   metadata.Description,
+		Website:       metadata.Website,
+		OracleAddress: oracle,
+		NetworkID:     network.MarketID,
+	}, nil
+}
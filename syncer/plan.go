@@ -0,0 +1,182 @@
+package syncer
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"market-sync/client"
+)
+
+type ActionType string
+
+const (
+	// ActionCreate creates a Market job for the job spec
+	ActionCreate ActionType = "create"
+	// ActionSkip leaves the job spec unpublished
+	ActionSkip ActionType = "skip"
+)
+
+// ReasonExists is the reason a job spec is skipped when it's already on the Market
+const ReasonExists = "exists on the Market"
+
+// Action is the proposed action for a single job spec on the node
+type Action struct {
+	Type ActionType               `json:"type"`
+	Spec *client.ChainlinkJobSpec `json:"spec"`
+	// Oracle is the oracle contract the job spec fulfils requests for
+	Oracle common.Address `json:"oracle"`
+	// Node is the Market node of the oracle, which the job is listed under
+	Node   *client.MarketNode `json:"node,omitempty"`
+	Reason string             `json:"reason,omitempty"`
+	// Fingerprint is the hash of the job spec's canonical content
+	Fingerprint string `json:"fingerprint"`
+	// PublishedFingerprint is the fingerprint listed with the spec's Market job, if
+	// it's on the Market and was published with one
+	PublishedFingerprint string `json:"publishedFingerprint,omitempty"`
+	// DuplicateOf is the ID of another job spec with the same fingerprint listed under
+	// the same Market node, if there is one. Duplicates are still created.
+	DuplicateOf string `json:"duplicateOf,omitempty"`
+}
+
+// Skip changes the action to leave the job spec unpublished
+func (a *Action) Skip(reason string) {
+	a.Type, a.Reason = ActionSkip, reason
+}
+
+// Changed returns whether the job spec has changed since it was published to the Market
+func (a *Action) Changed() bool {
+	return len(a.PublishedFingerprint) > 0 && a.PublishedFingerprint != a.Fingerprint
+}
+
+// Plan is the proposed action for every job spec on the node. The create actions'
+// specs can be changed, such as their name and cost, before the plan is applied.
+type Plan struct {
+	// Node is the Market node of the default oracle, which is nil if it has none
+	Node      *client.MarketNode `json:"node"`
+	SpecCount int                `json:"specCount"`
+	Actions   []*Action          `json:"actions"`
+}
+
+// Creates returns the create actions in the plan
+func (p *Plan) Creates() []*Action {
+	return p.filter(ActionCreate)
+}
+
+// Specs returns the job specs of the create actions in the plan
+func (p *Plan) Specs() []*client.ChainlinkJobSpec {
+	var specs []*client.ChainlinkJobSpec
+	for _, a := range p.Creates() {
+		specs = append(specs, a.Spec)
+	}
+	return specs
+}
+
+// Nodes returns every Market node in the plan
+func (p *Plan) Nodes() []*client.MarketNode {
+	var nodes []*client.MarketNode
+	seen := map[string]bool{}
+	if p.Node != nil {
+		nodes, seen[p.Node.ID.String()] = append(nodes, p.Node), true
+	}
+	for _, a := range p.Actions {
+		if a.Node != nil && !seen[a.Node.ID.String()] {
+			nodes, seen[a.Node.ID.String()] = append(nodes, a.Node), true
+		}
+	}
+	return nodes
+}
+
+// Changed returns the actions of job specs that have changed since they were published
+func (p *Plan) Changed() []*Action {
+	var actions []*Action
+	for _, a := range p.Actions {
+		if a.Changed() {
+			actions = append(actions, a)
+		}
+	}
+	return actions
+}
+
+// Shared returns the actions of this Chainlink node's job specs with the same fingerprint
+// that are listed under more than one of its Market nodes, being the specs of different
+// oracles on the node, grouped by fingerprint in the order they're first seen. The same
+// job on another Chainlink node isn't detected, as only this node's specs are planned.
+func (p *Plan) Shared() [][]*Action {
+	var order []string
+	groups := map[string][]*Action{}
+	nodes := map[string]map[string]bool{}
+	for _, a := range p.Actions {
+		if a.Node == nil {
+			continue
+		}
+		if _, ok := groups[a.Fingerprint]; !ok {
+			order = append(order, a.Fingerprint)
+			nodes[a.Fingerprint] = map[string]bool{}
+		}
+		groups[a.Fingerprint] = append(groups[a.Fingerprint], a)
+		nodes[a.Fingerprint][a.Node.ID.String()] = true
+	}
+	var shared [][]*Action
+	for _, f := range order {
+		if len(nodes[f]) > 1 {
+			shared = append(shared, groups[f])
+		}
+	}
+	return shared
+}
+
+// Duplicates returns the actions of job specs that are the same as another job spec
+// listed under the same Market node
+func (p *Plan) Duplicates() []*Action {
+	var actions []*Action
+	for _, a := range p.Actions {
+		if len(a.DuplicateOf) > 0 {
+			actions = append(actions, a)
+		}
+	}
+	return actions
+}
+
+// markDuplicates marks the job specs that are the same as another job spec listed
+// under the same Market node as duplicates of the one already on the Market if there
+// is one, otherwise the first
+func (p *Plan) markDuplicates() {
+	kept := map[string]*Action{}
+	for _, a := range p.Actions {
+		if a.Node == nil {
+			continue
+		}
+		key := a.Node.ID.String() + ":" + a.Fingerprint
+		if k, ok := kept[key]; !ok || (a.Reason == ReasonExists && k.Type == ActionCreate) {
+			kept[key] = a
+		}
+	}
+	for _, a := range p.Actions {
+		if a.Node == nil {
+			continue
+		}
+		if k := kept[a.Node.ID.String()+":"+a.Fingerprint]; k != a {
+			a.DuplicateOf = k.Spec.ID
+		}
+	}
+}
+
+// Skips returns the skip actions in the plan
+func (p *Plan) Skips() []*Action {
+	return p.filter(ActionSkip)
+}
+
+func (p *Plan) filter(t ActionType) []*Action {
+	var actions []*Action
+	for _, a := range p.Actions {
+		if a.Type == t {
+			actions = append(actions, a)
+		}
+	}
+	return actions
+}
+
+// Result is the outcome of applying a create action
+type Result struct {
+	Action    *Action
+	MarketJob *client.MarketCreated
+	Err       error
+}
@@ -0,0 +1,358 @@
+// Package syncer reconciles the job specs on a Chainlink node against the jobs
+// listed for the node on the Market, planning which job specs to publish and
+// then publishing them.
+//
+//	s := syncer.NewSyncer(chainlink, market, oracle)
+//	plan, err := s.Plan()
+//	...
+//	results, err := s.Apply(plan)
+package syncer
+
+import (
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	uuid "github.com/satori/go.uuid"
+	"market-sync/client"
+	"market-sync/provenance"
+)
+
+// ChainlinkAPI is the Chainlink node API used by the Syncer, satisfied by *client.Chainlink
+type ChainlinkAPI interface {
+	Config() (*client.ChainlinkConfig, error)
+	ETHKeys() (*client.ChainlinkETHKeys, error)
+	Balances() (*client.ChainlinkETHKeys, error)
+	GetSpecs(page, size int) (*client.ChainlinkJobSpecs, error)
+	SpecRunStats(id string, limit int) (*client.JobRunStats, error)
+}
+
+// MarketAPI is the Market API used by the Syncer to plan and publish, satisfied by *client.Market
+type MarketAPI interface {
+	CreateJob(spec *client.ChainlinkJobSpec) (*client.MarketCreated, error)
+	Jobs(nodeId uuid.UUID, page, size int) (*client.MarketJobPage, error)
+	JobExists(jobNodeId string, networkId int) (bool, error)
+	NodeByOracleAddress(oracle *common.Address, networkId int) (*client.MarketNode, error)
+}
+
+// MarketNodeAPI is the Market API used to register and update the node's listing, satisfied by *client.Market
+type MarketNodeAPI interface {
+	CreateNode(node *client.MarketNodeRequest) (*client.MarketCreated, error)
+	UpdateNode(nodeId uuid.UUID, node *client.MarketNodeRequest) error
+}
+
+var (
+	_ ChainlinkAPI  = (*client.Chainlink)(nil)
+	_ MarketAPI     = (*client.Market)(nil)
+	_ MarketNodeAPI = (*client.Market)(nil)
+)
+
+var (
+	ErrNilOracle    = errors.New("Chainlink oracle address is unknown, the node doesn't report its ORACLE_CONTRACT_ADDRESS so it must be passed in with chainlink-oracle-address")
+	ErrNodeNotFound = errors.New("Chainlink node not found on the Market, create it with node register before running this tool")
+)
+
+// InitiatorRunLog is the initiator of job specs that fulfil requests made to an oracle contract
+const InitiatorRunLog = "runlog"
+
+const (
+	// DefaultStatsSampleSize is how many of a job's most recent runs its stats are aggregated from
+	DefaultStatsSampleSize = 100
+)
+
+type Syncer struct {
+	chainlink ChainlinkAPI
+	market    MarketAPI
+	oracle    common.Address
+
+	nodeConfig *client.ChainlinkConfig
+	// nodes are the Market nodes of each oracle, which are nil if the oracle has no Market node
+	nodes map[common.Address]*client.MarketNode
+
+	// PageSize is how many job specs are read from the node per request
+	PageSize int
+	// StatsSampleSize is how many runs are sampled for the stats listed with a
+	// published job, with stats left out if zero
+	StatsSampleSize int
+	// Warn is called with any errors that don't stop the sync, if set
+	Warn func(err error)
+	// Networks maps the Chainlink node's chain ID to its Market network
+	Networks *NetworkRegistry
+	// Signer signs each job spec as it's published, with specs published unsigned if nil
+	Signer *provenance.Signer
+}
+
+// NewSyncer creates a Syncer for the oracle, defaulting to the node's oracle if it's
+// empty. The chainlink API can be nil if the Syncer is only used to Publish job specs.
+func NewSyncer(chainlink ChainlinkAPI, market MarketAPI, oracle common.Address) *Syncer {
+	return &Syncer{
+		chainlink:       chainlink,
+		market:          market,
+		oracle:          oracle,
+		nodes:           map[common.Address]*client.MarketNode{},
+		PageSize:        client.DefaultPageSize,
+		StatsSampleSize: DefaultStatsSampleSize,
+		Networks:        NewNetworkRegistry(),
+	}
+}
+
+// Refresh clears the node config and Market nodes read so far, so they're read again
+// by the next Plan, such as a Market node registered since the last one
+func (s *Syncer) Refresh() {
+	s.nodeConfig = nil
+	s.nodes = map[common.Address]*client.MarketNode{}
+}
+
+// NodeConfig returns the Chainlink node's config, which is only read once until Refresh is called
+func (s *Syncer) NodeConfig() (*client.ChainlinkConfig, error) {
+	if s.nodeConfig != nil {
+		return s.nodeConfig, nil
+	}
+	cfg, err := s.chainlink.Config()
+	if err != nil {
+		return nil, err
+	}
+	s.nodeConfig = cfg
+	return cfg, nil
+}
+
+// Oracle returns the oracle address the Syncer was created with, defaulting to the
+// ORACLE_CONTRACT_ADDRESS reported by the Chainlink node
+func (s *Syncer) Oracle() (common.Address, error) {
+	if s.oracle != (common.Address{}) {
+		return s.oracle, nil
+	}
+	cfg, err := s.NodeConfig()
+	if err != nil {
+		return common.Address{}, err
+	}
+	oracle, ok := cfg.Data.Attributes.OracleAddress()
+	if !ok {
+		return common.Address{}, ErrNilOracle
+	}
+	return oracle, nil
+}
+
+// Node returns the Market node for the default oracle address on the Chainlink node's chain
+func (s *Syncer) Node() (*client.MarketNode, error) {
+	oracle, err := s.Oracle()
+	if err != nil {
+		return nil, err
+	}
+	return s.NodeFor(oracle)
+}
+
+// NodeFor returns the Market node for the oracle address on the Chainlink node's chain
+func (s *Syncer) NodeFor(oracle common.Address) (*client.MarketNode, error) {
+	if node, ok := s.nodes[oracle]; ok {
+		if node == nil {
+			return nil, ErrNodeNotFound
+		}
+		return node, nil
+	}
+	network, err := s.Network()
+	if err != nil {
+		return nil, err
+	}
+	node, err := s.market.NodeByOracleAddress(&oracle, network.MarketID)
+	if errors.Is(err, client.ErrNotFound) {
+		s.nodes[oracle] = nil
+		return nil, ErrNodeNotFound
+	} else if err != nil {
+		return nil, err
+	} else if node.Network.ID != network.MarketID {
+		return nil, fmt.Errorf(
+			"%w: Market node %s is on Market network %d, but the Chainlink node's chain ID %d is Market network %d (%s)",
+			ErrNetworkMismatch,
+			node.ID.String(),
+			node.Network.ID,
+			network.ChainID,
+			network.MarketID,
+			network.Name,
+		)
+	}
+	s.nodes[oracle] = node
+	return node, nil
+}
+
+// Network returns the Market network of the Chainlink node's chain
+func (s *Syncer) Network() (*Network, error) {
+	cfg, err := s.NodeConfig()
+	if err != nil {
+		return nil, err
+	}
+	networks := s.Networks
+	if networks == nil {
+		networks = NewNetworkRegistry()
+	}
+	return networks.Lookup(cfg.Data.Attributes.ETHChainID)
+}
+
+// OracleFor returns the oracle address the job spec's runlog initiator listens
+// to, defaulting to the Syncer's oracle address for any other job spec
+func (s *Syncer) OracleFor(spec *client.ChainlinkJobSpec) (common.Address, error) {
+	for _, i := range spec.Attributes.Initiators {
+		if i.Type == InitiatorRunLog && i.Address != (common.Address{}) {
+			return i.Address, nil
+		}
+	}
+	return s.Oracle()
+}
+
+// Plan reads every job spec on the node, planning to create each job spec that
+// isn't already listed on the Market. Each job spec is listed under the Market
+// node of the oracle it fulfils requests for, and skipped if that oracle has no Market node.
+// The default oracle only needs a Market node if it has job specs to list, so a node
+// whose job specs all fulfil other oracles doesn't need to list or report it.
+func (s *Syncer) Plan() (*Plan, error) {
+	node, err := s.Node()
+	if err != nil && err != ErrNodeNotFound && err != ErrNilOracle {
+		return nil, err
+	}
+
+	plan := &Plan{Node: node}
+	published := &publishedJobs{market: s.market, pageSize: s.PageSize, nodes: map[uuid.UUID]map[string]*client.MarketJob{}}
+	it := client.NewSpecIterator(s.chainlink.GetSpecs, s.PageSize)
+	for it.Next() {
+		spec := it.Spec()
+		oracle, err := s.OracleFor(spec)
+		if err == ErrNilOracle {
+			reason := "no oracle address, pass the default oracle in with chainlink-oracle-address"
+			plan.Actions = append(plan.Actions, &Action{Type: ActionSkip, Spec: spec, Reason: reason})
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		node, err := s.NodeFor(oracle)
+		if err == ErrNodeNotFound {
+			reason := fmt.Sprintf("no Market node for oracle %s", oracle.String())
+			plan.Actions = append(plan.Actions, &Action{Type: ActionSkip, Spec: spec, Oracle: oracle, Reason: reason})
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		fingerprint, err := spec.ContentHash()
+		if err != nil {
+			return nil, err
+		}
+		action := &Action{Type: ActionCreate, Spec: spec, Oracle: oracle, Node: node, Fingerprint: fingerprint}
+		exists, err := s.market.JobExists(spec.ID, node.Network.ID)
+		if err != nil {
+			return nil, err
+		} else if exists {
+			action.Skip(ReasonExists)
+			jobs, err := published.jobs(node.ID)
+			if err != nil {
+				return nil, err
+			}
+			if job, ok := jobs[client.NormaliseJobID(spec.ID)]; ok {
+				action.PublishedFingerprint = job.Fingerprint
+			}
+		} else {
+			spec.NodeID = &node.ID
+			// the fingerprint listed is of the spec as it's read from the node, so changes
+			// made while publishing it aren't later reported as the spec having changed
+			spec.Fingerprint = fingerprint
+		}
+		plan.Actions = append(plan.Actions, action)
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	plan.SpecCount = it.Total()
+	plan.markDuplicates()
+	return plan, nil
+}
+
+// publishedJobs reads each Market node's jobs the first time they're needed, keyed
+// by their normalised node job ID
+type publishedJobs struct {
+	market   MarketAPI
+	pageSize int
+	nodes    map[uuid.UUID]map[string]*client.MarketJob
+}
+
+func (p *publishedJobs) jobs(nodeId uuid.UUID) (map[string]*client.MarketJob, error) {
+	if jobs, ok := p.nodes[nodeId]; ok {
+		return jobs, nil
+	}
+	jobs := map[string]*client.MarketJob{}
+	it := client.NewJobIterator(p.market.Jobs, nodeId, p.pageSize)
+	for it.Next() {
+		job := it.Job()
+		jobs[client.NormaliseJobID(job.NodeJobID)] = job
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	p.nodes[nodeId] = jobs
+	return jobs, nil
+}
+
+// Apply creates a Market job for every create action in the plan, under the action's
+// Market node or else the plan's, carrying on past any that fail. The results are
+// in the same order as the create actions.
+func (s *Syncer) Apply(plan *Plan) ([]*Result, error) {
+	if plan == nil {
+		return nil, errors.New("no plan to apply")
+	}
+	for _, action := range plan.Creates() {
+		if action.Node == nil && plan.Node == nil {
+			return nil, fmt.Errorf("job spec %s has no Market node", action.Spec.ID)
+		}
+	}
+	var results []*Result
+	for _, action := range plan.Creates() {
+		node := action.Node
+		if node == nil {
+			node = plan.Node
+		}
+		action.Spec.NodeID = &node.ID
+		s.AttachStats(action.Spec)
+		r := &Result{Action: action}
+		r.MarketJob, r.Err = s.Publish(action.Spec)
+		results = append(results, r)
+	}
+	return results, nil
+}
+
+// Publish signs the job spec, if there's a Signer, and creates its Market job under
+// the spec's Market node. It's the last step of publishing every job spec, whether
+// it's applied from a plan, prompted for or approved from the queue. Specs planned by
+// Plan keep the fingerprint of the spec read from the node, and any other spec is
+// fingerprinted as it's published.
+func (s *Syncer) Publish(spec *client.ChainlinkJobSpec) (*client.MarketCreated, error) {
+	if spec.NodeID == nil {
+		return nil, fmt.Errorf("job spec %s has no Market node", spec.ID)
+	}
+	if len(spec.Fingerprint) == 0 {
+		fingerprint, err := spec.ContentHash()
+		if err != nil {
+			return nil, err
+		}
+		spec.Fingerprint = fingerprint
+	}
+	if err := s.Signer.Sign(spec); err != nil {
+		return nil, err
+	}
+	return s.market.CreateJob(spec)
+}
+
+// AttachStats sets the job spec's run stats to be listed with its Market job,
+// warning if they can't be read
+func (s *Syncer) AttachStats(spec *client.ChainlinkJobSpec) {
+	if s.StatsSampleSize <= 0 {
+		return
+	}
+	if stats, err := s.chainlink.SpecRunStats(spec.ID, s.StatsSampleSize); err != nil {
+		s.warn(err)
+	} else {
+		spec.Stats = stats
+	}
+}
+
+func (s *Syncer) warn(err error) {
+	if s.Warn != nil {
+		s.Warn(err)
+	}
+}
@@ -0,0 +1,399 @@
+package syncer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	uuid "github.com/satori/go.uuid"
+	"market-sync/client"
+	"market-sync/provenance"
+	"testing"
+)
+
+var (
+	defaultOracle  = common.HexToAddress("0x1000000000000000000000000000000000000001")
+	secondOracle   = common.HexToAddress("0x2000000000000000000000000000000000000002")
+	unlistedOracle = common.HexToAddress("0x3000000000000000000000000000000000000003")
+)
+
+// mockChainlink serves the job specs and config of a node on mainnet
+type mockChainlink struct {
+	oracle common.Address
+	specs  []*client.ChainlinkJobSpec
+	// statsErr fails reading the run stats of every spec, if set
+	statsErr error
+}
+
+func (c *mockChainlink) Config() (*client.ChainlinkConfig, error) {
+	cfg := &client.ChainlinkConfig{}
+	err := json.Unmarshal([]byte(fmt.Sprintf(
+		`{"data": {"attributes": {"ethChainId": 1, "oracleContractAddress": %q}}}`,
+		c.oracle.Hex(),
+	)), cfg)
+	return cfg, err
+}
+
+func (c *mockChainlink) ETHKeys() (*client.ChainlinkETHKeys, error) {
+	return &client.ChainlinkETHKeys{}, nil
+}
+
+func (c *mockChainlink) Balances() (*client.ChainlinkETHKeys, error) {
+	return &client.ChainlinkETHKeys{}, nil
+}
+
+func (c *mockChainlink) GetSpecs(page, size int) (*client.ChainlinkJobSpecs, error) {
+	specs := &client.ChainlinkJobSpecs{Meta: client.ChainlinkMeta{Count: len(c.specs)}}
+	for i := (page - 1) * size; i < len(c.specs) && i < page*size; i++ {
+		specs.Data = append(specs.Data, c.specs[i])
+	}
+	return specs, nil
+}
+
+func (c *mockChainlink) SpecRunStats(id string, limit int) (*client.JobRunStats, error) {
+	if c.statsErr != nil {
+		return nil, c.statsErr
+	}
+	return &client.JobRunStats{Total: 10, Sampled: limit}, nil
+}
+
+// mockMarket lists a node for each oracle in nodes, and records the jobs created
+type mockMarket struct {
+	nodes map[common.Address]*client.MarketNode
+	// existing are the IDs of the node job specs already on the Market
+	existing map[string]bool
+	// fail are the IDs of the node job specs that fail to be created
+	fail    map[string]error
+	created []*client.ChainlinkJobSpec
+}
+
+func (m *mockMarket) CreateJob(spec *client.ChainlinkJobSpec) (*client.MarketCreated, error) {
+	if err := m.fail[spec.ID]; err != nil {
+		return nil, err
+	}
+	m.created = append(m.created, spec)
+	return &client.MarketCreated{ID: uuid.NewV4()}, nil
+}
+
+func (m *mockMarket) Jobs(nodeId uuid.UUID, page, size int) (*client.MarketJobPage, error) {
+	return &client.MarketJobPage{}, nil
+}
+
+func (m *mockMarket) JobExists(jobNodeId string, networkId int) (bool, error) {
+	return m.existing[jobNodeId], nil
+}
+
+func (m *mockMarket) NodeByOracleAddress(oracle *common.Address, networkId int) (*client.MarketNode, error) {
+	node, ok := m.nodes[*oracle]
+	if !ok {
+		return nil, client.ErrNotFound
+	}
+	return node, nil
+}
+
+func newMarketNode(oracle common.Address) *client.MarketNode {
+	n := &client.MarketNode{ID: uuid.NewV4(), OracleAddress: oracle}
+	n.Network.ID = 1
+	return n
+}
+
+func newSpec(id string, oracle common.Address, task string) *client.ChainlinkJobSpec {
+	spec := &client.ChainlinkJobSpec{ID: id}
+	spec.Attributes.Initiators = []*client.ChainlinkInitiator{{Type: InitiatorRunLog}}
+	spec.Attributes.Initiators[0].Address = oracle
+	spec.Attributes.Tasks = []*client.ChainlinkTaskSpec{{Type: task}}
+	return spec
+}
+
+func newTestSyncer(specs ...*client.ChainlinkJobSpec) (*Syncer, *mockChainlink, *mockMarket) {
+	c := &mockChainlink{oracle: defaultOracle, specs: specs}
+	m := &mockMarket{
+		nodes: map[common.Address]*client.MarketNode{
+			defaultOracle: newMarketNode(defaultOracle),
+			secondOracle:  newMarketNode(secondOracle),
+		},
+		existing: map[string]bool{},
+		fail:     map[string]error{},
+	}
+	s := NewSyncer(c, m, common.Address{})
+	s.PageSize = 2
+	return s, c, m
+}
+
+func TestSyncer_Plan(t *testing.T) {
+	s, _, m := newTestSyncer(
+		newSpec("a", defaultOracle, "httpget"),
+		newSpec("b", defaultOracle, "httppost"),
+		newSpec("c", unlistedOracle, "httpget"),
+		newSpec("d", secondOracle, "jsonparse"),
+		&client.ChainlinkJobSpec{ID: "e"},
+	)
+	m.existing["b"] = true
+
+	plan, err := s.Plan()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.SpecCount != 5 {
+		t.Errorf("expected 5 specs, got %d", plan.SpecCount)
+	}
+	if plan.Node != m.nodes[defaultOracle] {
+		t.Errorf("expected the plan's node to be the default oracle's")
+	}
+
+	tests := []struct {
+		id     string
+		action ActionType
+		reason string
+		node   *client.MarketNode
+	}{
+		{"a", ActionCreate, "", m.nodes[defaultOracle]},
+		{"b", ActionSkip, ReasonExists, m.nodes[defaultOracle]},
+		{"c", ActionSkip, "no Market node for oracle " + unlistedOracle.String(), nil},
+		{"d", ActionCreate, "", m.nodes[secondOracle]},
+		// specs without a runlog initiator are listed under the default oracle's node
+		{"e", ActionCreate, "", m.nodes[defaultOracle]},
+	}
+	if len(plan.Actions) != len(tests) {
+		t.Fatalf("expected %d actions, got %d", len(tests), len(plan.Actions))
+	}
+	for i, test := range tests {
+		a := plan.Actions[i]
+		if a.Spec.ID != test.id || a.Type != test.action || a.Reason != test.reason || a.Node != test.node {
+			t.Errorf(
+				"expected %s to %s (%q) under %v, got %s to %s (%q) under %v",
+				test.id, test.action, test.reason, test.node,
+				a.Spec.ID, a.Type, a.Reason, a.Node,
+			)
+		}
+		if a.Type == ActionCreate && (a.Spec.NodeID == nil || *a.Spec.NodeID != a.Node.ID) {
+			t.Errorf("expected %s to be listed under node %s, got %v", a.Spec.ID, a.Node.ID, a.Spec.NodeID)
+		}
+	}
+	if specs := plan.Specs(); len(specs) != 3 {
+		t.Errorf("expected 3 specs to create, got %d", len(specs))
+	}
+	if nodes := plan.Nodes(); len(nodes) != 2 {
+		t.Errorf("expected 2 Market nodes, got %d", len(nodes))
+	}
+}
+
+func TestSyncer_Plan_NetworkMismatch(t *testing.T) {
+	s, _, m := newTestSyncer(newSpec("a", defaultOracle, "httpget"))
+	m.nodes[defaultOracle].Network.ID = 42
+
+	if _, err := s.Plan(); !errors.Is(err, ErrNetworkMismatch) {
+		t.Errorf("expected a network mismatch, got %v", err)
+	}
+}
+
+func TestSyncer_Apply(t *testing.T) {
+	s, c, m := newTestSyncer(
+		newSpec("a", defaultOracle, "httpget"),
+		newSpec("b", defaultOracle, "httppost"),
+		newSpec("c", secondOracle, "jsonparse"),
+	)
+	failure := errors.New("market: validation failed")
+	m.fail["b"] = failure
+	c.statsErr = errors.New("runs unavailable")
+	var warnings []error
+	s.Warn = func(err error) { warnings = append(warnings, err) }
+
+	plan, err := s.Plan()
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, err := s.Apply(plan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected a result for every create, got %d", len(results))
+	}
+	for i, id := range []string{"a", "b", "c"} {
+		r := results[i]
+		if r.Action.Spec.ID != id {
+			t.Errorf("expected result %d to be for %s, got %s", i, id, r.Action.Spec.ID)
+		}
+		if id == "b" {
+			if r.Err != failure || r.MarketJob != nil {
+				t.Errorf("expected b to fail with %v, got %v", failure, r.Err)
+			}
+		} else if r.Err != nil || r.MarketJob == nil {
+			t.Errorf("expected %s to be created, got %v", id, r.Err)
+		}
+	}
+	if len(m.created) != 2 {
+		t.Errorf("expected the jobs after the failure to be created, got %d created", len(m.created))
+	}
+	if *m.created[1].NodeID != m.nodes[secondOracle].ID {
+		t.Errorf("expected c to be created under the second oracle's node")
+	}
+	if len(warnings) != 3 {
+		t.Errorf("expected a warning for each spec's stats, got %d", len(warnings))
+	}
+}
+
+func TestSyncer_Apply_AttachesStats(t *testing.T) {
+	s, _, m := newTestSyncer(newSpec("a", defaultOracle, "httpget"))
+	s.StatsSampleSize = 25
+
+	plan, err := s.Plan()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Apply(plan); err != nil {
+		t.Fatal(err)
+	}
+	if len(m.created) != 1 || m.created[0].Stats == nil || m.created[0].Stats.Sampled != 25 {
+		t.Errorf("expected the job to be created with stats sampled from 25 runs")
+	}
+}
+
+func TestSyncer_Apply_Invalid(t *testing.T) {
+	s, _, _ := newTestSyncer()
+	if _, err := s.Apply(nil); err == nil {
+		t.Error("expected an error applying a nil plan")
+	}
+	plan := &Plan{Actions: []*Action{{Type: ActionCreate, Spec: newSpec("a", defaultOracle, "httpget")}}}
+	if _, err := s.Apply(plan); err == nil {
+		t.Error("expected an error applying a create without a Market node")
+	}
+}
+
+func TestSyncer_Publish(t *testing.T) {
+	s, _, m := newTestSyncer()
+	spec := newSpec("a", defaultOracle, "httpget")
+	if _, err := s.Publish(spec); err == nil {
+		t.Error("expected an error publishing a spec without a Market node")
+	}
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Signer = provenance.NewSigner(key)
+	nodeId := m.nodes[defaultOracle].ID
+	spec.NodeID = &nodeId
+	if _, err := s.Publish(spec); err != nil {
+		t.Fatal(err)
+	}
+	if len(m.created) != 1 || m.created[0].Provenance == nil {
+		t.Fatal("expected the job to be created with its provenance")
+	}
+	if signer, err := provenance.Recover(m.created[0].Provenance); err != nil || signer != s.Signer.Address() {
+		t.Errorf("expected the job to be signed by %s, got %s, %v", s.Signer.Address().String(), signer.String(), err)
+	}
+}
+
+func TestSyncer_Plan_UnlistedDefaultOracle(t *testing.T) {
+	s, _, m := newTestSyncer(
+		newSpec("a", secondOracle, "httpget"),
+		&client.ChainlinkJobSpec{ID: "b"},
+	)
+	delete(m.nodes, defaultOracle)
+
+	plan, err := s.Plan()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Node != nil {
+		t.Errorf("expected no default node, got %v", plan.Node)
+	}
+	if len(plan.Actions) != 2 || plan.Actions[0].Type != ActionCreate || plan.Actions[0].Node != m.nodes[secondOracle] {
+		t.Fatalf("expected a to be created under the second oracle's node")
+	}
+	if a := plan.Actions[1]; a.Type != ActionSkip || a.Reason != "no Market node for oracle "+defaultOracle.String() {
+		t.Errorf("expected b to be skipped for having no Market node, got %s (%q)", a.Type, a.Reason)
+	}
+}
+
+func TestSyncer_Plan_UnknownDefaultOracle(t *testing.T) {
+	s, c, _ := newTestSyncer(
+		newSpec("a", secondOracle, "httpget"),
+		&client.ChainlinkJobSpec{ID: "b"},
+	)
+	c.oracle = common.Address{}
+
+	plan, err := s.Plan()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Actions) != 2 || plan.Actions[0].Type != ActionCreate || plan.Actions[1].Type != ActionSkip {
+		t.Fatalf("expected a to be created and b skipped without an oracle address")
+	}
+}
+
+func TestSyncer_Plan_Duplicates(t *testing.T) {
+	s, _, m := newTestSyncer(
+		newSpec("a", defaultOracle, "httpget"),
+		newSpec("b", defaultOracle, "httpget"),
+		newSpec("c", secondOracle, "httpget"),
+		newSpec("d", defaultOracle, "httpget"),
+	)
+	m.existing["b"] = true
+
+	plan, err := s.Plan()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the spec already on the Market is kept over the first, and specs on other nodes aren't duplicates
+	for i, want := range []string{"b", "", "", "b"} {
+		if a := plan.Actions[i]; a.DuplicateOf != want {
+			t.Errorf("expected %s to be a duplicate of %q, got %q", a.Spec.ID, want, a.DuplicateOf)
+		}
+	}
+	if len(plan.Duplicates()) != 2 {
+		t.Errorf("expected 2 duplicates, got %d", len(plan.Duplicates()))
+	}
+	if specs := plan.Specs(); len(specs) != 3 {
+		t.Errorf("expected duplicates to still be created, got %d creates", len(specs))
+	}
+}
+
+func TestSyncer_Apply_FingerprintsSpecAsRead(t *testing.T) {
+	spec := newSpec("a", defaultOracle, "httpget")
+	s, _, m := newTestSyncer(spec)
+	want, err := spec.ContentHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plan, err := s.Plan()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// parameters edited before publishing don't change the fingerprint listed
+	spec.Attributes.Tasks[0].Params = map[string]interface{}{"get": "https://example.com"}
+	if _, err := s.Apply(plan); err != nil {
+		t.Fatal(err)
+	}
+	if len(m.created) != 1 || m.created[0].Fingerprint != want {
+		t.Errorf("expected the fingerprint of the spec as read, %s", want)
+	}
+}
+
+func TestSyncer_Refresh(t *testing.T) {
+	s, _, m := newTestSyncer(newSpec("a", unlistedOracle, "httpget"))
+	plan, err := s.Plan()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Actions[0].Type != ActionSkip {
+		t.Fatalf("expected a to be skipped without a Market node, got %s", plan.Actions[0].Type)
+	}
+
+	m.nodes[unlistedOracle] = newMarketNode(unlistedOracle)
+	if plan, err = s.Plan(); err != nil {
+		t.Fatal(err)
+	} else if plan.Actions[0].Type != ActionSkip {
+		t.Errorf("expected the missing Market node to stay cached until refreshed")
+	}
+	s.Refresh()
+	if plan, err = s.Plan(); err != nil {
+		t.Fatal(err)
+	} else if plan.Actions[0].Type != ActionCreate || plan.Actions[0].Node != m.nodes[unlistedOracle] {
+		t.Errorf("expected a to be created under the Market node registered since the last plan")
+	}
+}
@@ -0,0 +1,25 @@
+package syncer
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"market-sync/chain"
+)
+
+// VerifyOracles checks the oracle contract of every Market node in the plan exists
+// on chain, and which of the Chainlink node's ETH keys are authorized to fulfil its requests
+func (s *Syncer) VerifyOracles(ctx context.Context, backend bind.ContractBackend, plan *Plan) ([]*chain.Verification, error) {
+	keys, err := s.chainlink.ETHKeys()
+	if err != nil {
+		return nil, err
+	}
+	var verifications []*chain.Verification
+	for _, node := range plan.Nodes() {
+		v, err := chain.Verify(ctx, backend, node.OracleAddress, keys.Addresses())
+		if err != nil {
+			return nil, err
+		}
+		verifications = append(verifications, v)
+	}
+	return verifications, nil
+}
@@ -0,0 +1,529 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/fatih/color"
+	"golang.org/x/crypto/ssh/terminal"
+	"io"
+	"market-sync/client"
+	"os"
+	"strings"
+)
+
+type reviewStatus int
+
+const (
+	reviewPending reviewStatus = iota
+	reviewApproved
+	reviewSkipped
+	reviewPublished
+	reviewFailed
+)
+
+func (s reviewStatus) String() string {
+	switch s {
+	case reviewApproved:
+		return "approved"
+	case reviewSkipped:
+		return "skipped"
+	case reviewPublished:
+		return "published"
+	case reviewFailed:
+		return "failed"
+	default:
+		return "pending"
+	}
+}
+
+// reviewFilters are cycled through to filter the list by status, -1 shows every status
+var reviewFilters = []reviewStatus{-1, reviewPending, reviewApproved, reviewSkipped}
+
+const reviewHelp = "↑/↓ move  a approve  s skip  e edit  n name  c cost  d diff  / search  f filter  x submit  q quit"
+
+type reviewItem struct {
+	spec     *client.ChainlinkJobSpec
+	original string
+	status   reviewStatus
+	err      error
+}
+
+// reviewTUI is a full screen terminal UI to review every unsynced job spec at once,
+// approving the ones to be published and then submitting them in a single batch
+type reviewTUI struct {
+	app     *Application
+	items   []*reviewItem
+	cursor  int
+	offset  int
+	search  string
+	filter  int
+	diff    bool
+	message string
+	fd      int
+	state   *terminal.State
+	in      io.Reader
+	width   int
+	height  int
+}
+
+func newReviewTUI(app *Application, specs []*client.ChainlinkJobSpec) *reviewTUI {
+	t := &reviewTUI{
+		app: app,
+		fd:  int(os.Stdin.Fd()),
+		in:  os.Stdin,
+	}
+	for _, spec := range specs {
+		t.items = append(t.items, &reviewItem{spec: spec, original: prettySpec(spec)})
+	}
+	return t
+}
+
+// Run shows the UI until the user submits or quits, returning whether the approved specs should be published
+func (t *reviewTUI) Run() (bool, error) {
+	if !terminal.IsTerminal(t.fd) {
+		return false, errors.New("the review UI requires an interactive terminal")
+	}
+	if err := t.enter(); err != nil {
+		return false, err
+	}
+	defer t.leave()
+
+	for {
+		t.render()
+		key, err := t.readKey()
+		if err != nil {
+			return false, err
+		}
+		t.message = ""
+		switch key {
+		case "up", "k":
+			t.move(-1)
+		case "down", "j":
+			t.move(1)
+		case "a":
+			t.approve()
+		case "s":
+			t.setStatus(reviewSkipped)
+		case "e":
+			_ = t.prompt(func(item *reviewItem) error {
+				e := newSpecEditor(t.app.config.Prompter, item.spec)
+				e.onChange = t.app.saveProgress
+				return e.Run()
+			})
+		case "n":
+			_ = t.prompt(func(item *reviewItem) error {
+				name, err := t.app.promptJobName(item.spec)
+				if err == nil {
+					item.spec.Name = name
+				}
+				return err
+			})
+		case "c":
+			_ = t.prompt(func(item *reviewItem) error {
+				cost, err := t.app.promptJobCost()
+				if err == nil {
+					item.spec.MinPayment = cost
+				}
+				return err
+			})
+		case "d":
+			t.diff = !t.diff
+		case "f":
+			t.filter = (t.filter + 1) % len(reviewFilters)
+			t.cursor, t.offset = 0, 0
+		case "/":
+			if err := t.readSearch(); err != nil {
+				return false, err
+			}
+		case "x":
+			if t.count(reviewApproved) == 0 {
+				t.message = "No job specs have been approved"
+				continue
+			}
+			return true, nil
+		case "q", "ctrl-c":
+			return false, nil
+		}
+	}
+}
+
+// Approved returns the specs that were approved, in the order they were listed
+func (t *reviewTUI) Approved() []*reviewItem {
+	var approved []*reviewItem
+	for _, item := range t.items {
+		if item.status == reviewApproved {
+			approved = append(approved, item)
+		}
+	}
+	return approved
+}
+
+func (t *reviewTUI) enter() error {
+	state, err := terminal.MakeRaw(t.fd)
+	if err != nil {
+		return err
+	}
+	t.state = state
+	fmt.Print("\x1b[?1049h\x1b[?25l")
+	return nil
+}
+
+func (t *reviewTUI) leave() {
+	fmt.Print("\x1b[?25h\x1b[?1049l")
+	if t.state != nil {
+		_ = terminal.Restore(t.fd, t.state)
+		t.state = nil
+	}
+}
+
+// prompt leaves the full screen UI to run the regular prompts against the selected
+// spec, showing any error as the message and returning it
+func (t *reviewTUI) prompt(fn func(item *reviewItem) error) error {
+	item := t.selected()
+	if item == nil {
+		return errors.New("no job spec is selected")
+	}
+	t.leave()
+	fmt.Print("\x1b[H\x1b[2J")
+	t.app.outputJSON(item.spec)
+	promptErr := fn(item)
+	if err := t.enter(); err != nil {
+		t.message = err.Error()
+		return err
+	} else if promptErr != nil {
+		t.message = promptErr.Error()
+	}
+	return promptErr
+}
+
+func (t *reviewTUI) approve() {
+	item := t.selected()
+	if item == nil {
+		return
+	}
+	// the spec is only approved once it has both a name and cost
+	if len(item.spec.Name) == 0 {
+		if err := t.prompt(func(item *reviewItem) error {
+			name, err := t.app.promptJobName(item.spec)
+			if err == nil {
+				item.spec.Name = name
+			}
+			return err
+		}); err != nil {
+			return
+		}
+	}
+	if len(item.spec.MinPayment) == 0 {
+		if err := t.prompt(func(item *reviewItem) error {
+			cost, err := t.app.promptJobCost()
+			if err == nil {
+				item.spec.MinPayment = cost
+			}
+			return err
+		}); err != nil {
+			return
+		}
+	}
+	t.setStatus(reviewApproved)
+}
+
+func (t *reviewTUI) setStatus(status reviewStatus) {
+	item := t.selected()
+	if item == nil {
+		return
+	}
+	item.status = status
+	if t.selected() == item {
+		t.move(1)
+	} else {
+		t.move(0)
+	}
+}
+
+func (t *reviewTUI) visible() []*reviewItem {
+	var items []*reviewItem
+	status := reviewFilters[t.filter]
+	search := strings.ToLower(t.search)
+	for _, item := range t.items {
+		if status >= 0 && item.status != status {
+			continue
+		}
+		if len(search) > 0 && !strings.Contains(strings.ToLower(item.summary()), search) {
+			continue
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+func (t *reviewTUI) selected() *reviewItem {
+	items := t.visible()
+	if t.cursor < 0 || t.cursor >= len(items) {
+		return nil
+	}
+	return items[t.cursor]
+}
+
+func (t *reviewTUI) move(delta int) {
+	n := len(t.visible())
+	t.cursor += delta
+	if t.cursor >= n {
+		t.cursor = n - 1
+	}
+	if t.cursor < 0 {
+		t.cursor = 0
+	}
+}
+
+func (t *reviewTUI) count(status reviewStatus) int {
+	var n int
+	for _, item := range t.items {
+		if item.status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// readByte reads from stdin unbuffered, so nothing is lost when the regular prompts read from it
+func (t *reviewTUI) readByte() (byte, error) {
+	b := make([]byte, 1)
+	_, err := io.ReadFull(t.in, b)
+	return b[0], err
+}
+
+func (t *reviewTUI) readKey() (string, error) {
+	b, err := t.readByte()
+	if err != nil {
+		return "", err
+	}
+	switch b {
+	case 3:
+		return "ctrl-c", nil
+	case 13, 10:
+		return "enter", nil
+	case 127, 8:
+		return "backspace", nil
+	case 27:
+		if next, err := t.readByte(); err != nil {
+			return "", err
+		} else if next != '[' {
+			return "esc", nil
+		}
+		code, err := t.readByte()
+		if err != nil {
+			return "", err
+		}
+		switch code {
+		case 'A':
+			return "up", nil
+		case 'B':
+			return "down", nil
+		}
+		return "esc", nil
+	}
+	return string(b), nil
+}
+
+// readSearch reads the search text inline, until enter is pressed
+func (t *reviewTUI) readSearch() error {
+	t.search = ""
+	for {
+		t.message = "Search: " + t.search
+		t.render()
+		key, err := t.readKey()
+		if err != nil {
+			return err
+		}
+		switch key {
+		case "enter", "esc", "ctrl-c":
+			t.message = ""
+			t.cursor, t.offset = 0, 0
+			return nil
+		case "backspace":
+			if len(t.search) > 0 {
+				t.search = t.search[:len(t.search)-1]
+			}
+		default:
+			if len(key) == 1 && key[0] >= 32 && key[0] < 127 {
+				t.search += key
+			}
+		}
+	}
+}
+
+func (t *reviewTUI) render() {
+	t.width, t.height = 100, 30
+	if w, h, err := terminal.GetSize(t.fd); err == nil {
+		t.width, t.height = w, h
+	}
+	bold := color.New(color.Bold).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+
+	var lines []string
+	filter := "all"
+	if status := reviewFilters[t.filter]; status >= 0 {
+		filter = status.String()
+	}
+	lines = append(lines,
+		bold(fmt.Sprintf(
+			"Market Sync: %d job specs, %d approved, %d skipped  [filter: %s, search: %q]",
+			len(t.items),
+			t.count(reviewApproved),
+			t.count(reviewSkipped),
+			filter,
+			t.search,
+		)),
+		yellow(truncate(reviewHelp, t.width)),
+		"",
+	)
+
+	items := t.visible()
+	listHeight := (t.height - 5) / 3
+	if listHeight < 3 {
+		listHeight = 3
+	}
+	if t.cursor < t.offset {
+		t.offset = t.cursor
+	} else if t.cursor >= t.offset+listHeight {
+		t.offset = t.cursor - listHeight + 1
+	}
+	for i := t.offset; i < len(items) && i < t.offset+listHeight; i++ {
+		line := truncate(fmt.Sprintf("  %-10s %s", items[i].status, items[i].summary()), t.width)
+		if i == t.cursor {
+			line = color.New(color.ReverseVideo).Sprint(line)
+		}
+		lines = append(lines, line)
+	}
+	if len(items) == 0 {
+		lines = append(lines, "  No job specs match the filter")
+	}
+	for len(lines) < listHeight+3 {
+		lines = append(lines, "")
+	}
+	lines = append(lines, strings.Repeat("─", t.width))
+
+	if item := t.selected(); item != nil {
+		lines = append(lines, t.preview(item)...)
+	}
+	if len(lines) > t.height-1 {
+		lines = lines[:t.height-1]
+	}
+	for len(lines) < t.height-1 {
+		lines = append(lines, "")
+	}
+	lines = append(lines, color.New(color.FgRed).Sprint(truncate(t.message, t.width)))
+
+	fmt.Print("\x1b[H\x1b[2J" + strings.Join(lines, "\r\n"))
+}
+
+func (t *reviewTUI) preview(item *reviewItem) []string {
+	yellow := color.New(color.FgYellow).SprintFunc()
+	cost := "-"
+	if c, err := client.ParseLink(item.spec.MinPayment); err == nil && len(item.spec.MinPayment) > 0 {
+		cost = c.Display()
+	}
+	lines := []string{
+		fmt.Sprintf("%s %s", yellow("Job ID:"), item.spec.ID),
+		fmt.Sprintf("%s %s", yellow("Name:"), item.spec.Name),
+		fmt.Sprintf("%s %s", yellow("Cost:"), cost),
+	}
+	if item.err != nil {
+		lines = append(lines, color.RedString("Error: %s", item.err))
+	}
+	lines = append(lines, "")
+
+	current := prettySpec(item.spec)
+	if !t.diff {
+		for _, l := range strings.Split(current, "\n") {
+			lines = append(lines, truncate(l, t.width))
+		}
+		return lines
+	}
+	diff := diffLines(item.original, current)
+	if len(diff) == 0 {
+		return append(lines, "No changes to the job spec")
+	}
+	for _, l := range diff {
+		l = truncate(l, t.width)
+		switch {
+		case strings.HasPrefix(l, "+"):
+			l = color.GreenString(l)
+		case strings.HasPrefix(l, "-"):
+			l = color.RedString(l)
+		}
+		lines = append(lines, l)
+	}
+	return lines
+}
+
+func (i *reviewItem) summary() string {
+	return fmt.Sprintf(
+		"%s  %-30s  %s -> %s",
+		i.spec.ID,
+		i.spec.Name,
+		strings.Join(initiatorTypes(i.spec), ","),
+		strings.Join(taskTypes(i.spec), ","),
+	)
+}
+
+func prettySpec(spec *client.ChainlinkJobSpec) string {
+	b, _ := json.MarshalIndent(spec.Attributes, "", "  ")
+	return string(b)
+}
+
+func truncate(s string, width int) string {
+	r := []rune(s)
+	if width > 0 && len(r) > width {
+		return string(r[:width])
+	}
+	return s
+}
+
+// diffLines returns the lines that differ between a and b, prefixed with - or +,
+// along with the unchanged lines between them, based on their longest common subsequence
+func diffLines(a, b string) []string {
+	if a == b {
+		return nil
+	}
+	x, y := strings.Split(a, "\n"), strings.Split(b, "\n")
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	var diff []string
+	i, j := 0, 0
+	for i < len(x) && j < len(y) {
+		switch {
+		case x[i] == y[j]:
+			diff = append(diff, "  "+x[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			diff = append(diff, "- "+x[i])
+			i++
+		default:
+			diff = append(diff, "+ "+y[j])
+			j++
+		}
+	}
+	for ; i < len(x); i++ {
+		diff = append(diff, "- "+x[i])
+	}
+	for ; j < len(y); j++ {
+		diff = append(diff, "+ "+y[j])
+	}
+	return diff
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/fatih/color"
+	uuid "github.com/satori/go.uuid"
+	"github.com/spf13/cobra"
+	"market-sync/chain"
+	"market-sync/client"
+	"market-sync/provenance"
+	"market-sync/syncer"
+	"time"
+)
+
+// verifyTimeout is how long reading every oracle contract in the plan can take
+const verifyTimeout = 30 * time.Second
+
+// verifyOracles checks every oracle being synced to is deployed, and that one of the
+// node's keys can fulfil its requests, remembering any that can't so their job specs
+// are warned about before they're published
+func (a *Application) verifyOracles(plan *syncer.Plan) error {
+	a.unfulfillable = map[common.Address]error{}
+	if len(a.config.ETHRPCURL) == 0 || len(plan.Creates()) == 0 {
+		return nil
+	}
+	backend, err := a.ethBackend()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), verifyTimeout)
+	defer cancel()
+	verifications, err := a.syncer.VerifyOracles(ctx, backend, plan)
+	if err != nil {
+		color.Red("Warning: unable to verify the oracle contracts on chain")
+		displayError(err)
+		return nil
+	}
+
+	yellow := color.New(color.FgYellow).SprintFunc()
+	for _, v := range verifications {
+		if err := v.Err(); err != nil {
+			a.unfulfillable[v.Oracle] = err
+			color.Red("Warning: %s", err)
+			continue
+		}
+		for _, k := range v.Keys {
+			if k.Authorized {
+				fmt.Printf("%s %s (oracle %s)\n", yellow("Authorized Key:"), k.Address.String(), v.Oracle.String())
+			}
+		}
+	}
+	fmt.Println()
+	return nil
+}
+
+// ethBackend connects to the Ethereum RPC the first time it's used, returning nil if its URL isn't set
+func (a *Application) ethBackend() (chain.Backend, error) {
+	if a.backend != nil || len(a.config.ETHRPCURL) == 0 {
+		return a.backend, nil
+	}
+	backend, err := chain.Dial(a.config.ETHRPCURL)
+	if err != nil {
+		return nil, err
+	}
+	a.backend = backend
+	return backend, nil
+}
+
+// checkOracle warns if the job spec's oracle failed verification, as the job could never be fulfilled
+func (a *Application) checkOracle(spec *client.ChainlinkJobSpec) {
+	node := a.nodeFor(spec)
+	if node == nil {
+		return
+	}
+	if err := a.unfulfillable[node.OracleAddress]; err != nil {
+		color.Red("Warning: job spec %s could never be fulfilled, %s", spec.ID, err)
+	}
+}
+
+func generateVerifyCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "verify [market job id]",
+		Short: "Check a Market listing was signed by the owner of its node's oracle contract",
+		Args:  cobra.ExactArgs(1),
+		Run:   runVerify,
+	}
+}
+
+func runVerify(_ *cobra.Command, args []string) {
+	yellow := color.New(color.FgYellow).SprintFunc()
+	requireFlags(append(marketFlags, ETHRPCURLFlag)...)
+	jobId, err := uuid.FromString(args[0])
+	if err != nil {
+		exit(fmt.Errorf("invalid Market job ID %q: %v", args[0], err))
+	}
+	a, err := NewApplication(newConfig(&Config{}))
+	if err != nil {
+		exit(err)
+	}
+	job, node, err := a.VerifyListing(jobId)
+	if job != nil {
+		fmt.Printf("%s %s\n", yellow("Job Name:"), job.Name)
+	}
+	if node != nil {
+		fmt.Printf("%s %s\n", yellow("Oracle Address:"), node.OracleAddress.String())
+	}
+	if job != nil && job.Provenance != nil {
+		fmt.Printf("%s %s\n", yellow("Signer:"), job.Provenance.Signer.String())
+	}
+	if err != nil {
+		exit(err)
+	}
+	color.Green("Listing %s was signed by the owner of its oracle", jobId.String())
+	exit(nil)
+}
+
+// VerifyListing checks the Market job's provenance was signed for the job by the
+// owner of its node's oracle contract, and that the job is listed as it was signed,
+// returning the job and node it read
+func (a *Application) VerifyListing(jobId uuid.UUID) (*client.MarketJob, *client.MarketNode, error) {
+	job, err := a.market.Job(jobId)
+	if err != nil {
+		return nil, nil, err
+	}
+	signer, err := provenance.Recover(job.Provenance)
+	if err != nil {
+		return job, nil, err
+	} else if err := provenance.MatchesListing(job); err != nil {
+		return job, nil, err
+	}
+	node, err := a.market.Node(job.NodeID)
+	if err != nil {
+		return job, nil, err
+	}
+
+	backend, err := a.ethBackend()
+	if err != nil {
+		return job, node, err
+	}
+	oracle, err := chain.NewOracle(node.OracleAddress, backend)
+	if err != nil {
+		return job, node, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), verifyTimeout)
+	defer cancel()
+	owner, err := oracle.Owner(ctx)
+	if err != nil {
+		return job, node, err
+	} else if owner != signer {
+		return job, node, fmt.Errorf(
+			"%w: signed by %s, but oracle %s is owned by %s",
+			provenance.ErrInvalidSignature,
+			signer.String(),
+			node.OracleAddress.String(),
+			owner.String(),
+		)
+	}
+	return job, node, nil
+}
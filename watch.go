@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"market-sync/client"
+	"time"
+)
+
+const WatchIntervalFlag = "interval"
+
+func generateWatchCmd() *cobra.Command {
+	newcmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Continuously reconcile the node against the Market, reporting job specs that aren't synced",
+		Args:  cobra.MaximumNArgs(0),
+		Run:   runWatch,
+	}
+	newcmd.Flags().Duration(WatchIntervalFlag, 5*time.Minute, "time between each reconcile")
+	addMetricsFlag(newcmd)
+	return newcmd
+}
+
+func runWatch(_ *cobra.Command, _ []string) {
+	yellow := color.New(color.FgYellow).SprintFunc()
+	color.Blue("Starting the Market Sync watcher")
+	requireFlags(append(nodeFlags, marketFlags...)...)
+	a, err := NewApplication(serveMetrics(newConfig(&Config{})))
+	if err != nil {
+		exit(err)
+	}
+	node, err := a.MarketNode()
+	if err != nil {
+		exit(err)
+	}
+	if node != nil {
+		fmt.Printf("%s %s\n", yellow("Market Node ID:"), node.ID.String())
+	}
+	a.Watch(viper.GetDuration(WatchIntervalFlag))
+}
+
+// Watch reconciles the node against the Market on every interval, reporting any
+// job specs that weren't unsynced at the previous reconcile. It never publishes any jobs.
+func (a *Application) Watch(interval time.Duration) {
+	known := map[string]bool{}
+	for {
+		// the node's config and Market nodes are read again, as either can change while watching
+		a.syncer.Refresh()
+		if plan, err := a.plan(); err != nil {
+			displayError(err)
+		} else {
+			specs := plan.Specs()
+			unsynced := map[string]bool{}
+			var added []*client.ChainlinkJobSpec
+			for _, spec := range specs {
+				unsynced[spec.ID] = true
+				if !known[spec.ID] {
+					color.Yellow("New unsynced job spec: %s", spec.ID)
+					added = append(added, spec)
+				}
+			}
+			if len(added) > 0 {
+				a.notifyUnsynced(added)
+				for _, spec := range added {
+					a.checkSecrets(spec)
+				}
+			}
+			known = unsynced
+		}
+		time.Sleep(interval)
+	}
+}